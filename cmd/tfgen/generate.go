@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"terraform-provider-edgedelta/edgedelta"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Files written by generate, relative to the output directory
+const (
+	importsFile    = "imports.tf"
+	configsFile    = "configs.tf"
+	dashboardsFile = "dashboards.tf"
+	configsDir     = "configs"
+	dashboardsDir  = "dashboards"
+)
+
+// generate returns the Terraform files that bring configs and dashboards under Terraform,
+// keyed by their path relative to the output directory. Config contents are written as
+// side-car YAML files and dashboard definitions as JSON files, both loaded with file().
+func generate(configs []*edgedelta.Config, dashboards []*edgedelta.Dashboard) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	imports := hclwrite.NewEmptyFile()
+
+	if len(configs) > 0 {
+		sorted := append([]*edgedelta.Config(nil), configs...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Tag < sorted[j].Tag })
+
+		f := hclwrite.NewEmptyFile()
+		names := newResourceNames()
+		for _, c := range sorted {
+			name := names.next(c.Tag, "config")
+			contentPath := path.Join(configsDir, name+".yaml")
+			files[contentPath] = []byte(c.Content)
+
+			body := f.Body().AppendNewBlock("resource", []string{"edgedelta_config", name}).Body()
+			body.SetAttributeValue("conf_id", cty.StringVal(c.ID))
+			body.SetAttributeRaw("config_content", fileCallTokens(contentPath))
+			body.SetAttributeValue("environment", cty.StringVal(string(c.Environment)))
+			if c.FleetType != "" {
+				body.SetAttributeValue("fleet_type", cty.StringVal(string(c.FleetType)))
+			}
+			if c.FleetSubtype != "" {
+				body.SetAttributeValue("fleet_subtype", cty.StringVal(string(c.FleetSubtype)))
+			}
+			if c.ClusterName != "" {
+				body.SetAttributeValue("cluster_name", cty.StringVal(c.ClusterName))
+			}
+			if c.Description != "" {
+				body.SetAttributeValue("description", cty.StringVal(c.Description))
+			}
+			// Terraform didn't create the configs, so they are adopted like imported configs and
+			// a destroy leaves them in place, see the edgedelta_config import docs
+			body.SetAttributeValue("lifecycle_mode", cty.StringVal(string(edgedelta.AdoptLifecycleMode)))
+			f.Body().AppendNewline()
+
+			appendImportBlock(imports.Body(), "edgedelta_config", name, c.ID)
+		}
+		files[configsFile] = hclwrite.Format(f.Bytes())
+	}
+
+	if len(dashboards) > 0 {
+		sorted := append([]*edgedelta.Dashboard(nil), dashboards...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].DashboardName < sorted[j].DashboardName })
+
+		f := hclwrite.NewEmptyFile()
+		names := newResourceNames()
+		for _, dash := range sorted {
+			name := names.next(dash.DashboardName, "dashboard")
+			body := f.Body().AppendNewBlock("resource", []string{"edgedelta_dashboard", name}).Body()
+			body.SetAttributeValue("dashboard_name", cty.StringVal(dash.DashboardName))
+			if dash.Description != "" {
+				body.SetAttributeValue("description", cty.StringVal(dash.Description))
+			}
+			if len(dash.Tags) > 0 {
+				tags := make([]cty.Value, len(dash.Tags))
+				for i, t := range dash.Tags {
+					tags[i] = cty.StringVal(t)
+				}
+				body.SetAttributeValue("tags", cty.ListVal(tags))
+			}
+			definition, err := dashboardDefinitionJSON(dash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode the definition of dashboard '%s': %s", dash.DashboardID, err)
+			}
+			if definition != nil {
+				definitionPath := path.Join(dashboardsDir, name+".json")
+				files[definitionPath] = definition
+				body.SetAttributeRaw("definition", fileCallTokens(definitionPath))
+			}
+			f.Body().AppendNewline()
+
+			appendImportBlock(imports.Body(), "edgedelta_dashboard", name, dash.DashboardID)
+		}
+		files[dashboardsFile] = hclwrite.Format(f.Bytes())
+	}
+
+	if len(files) > 0 {
+		files[importsFile] = hclwrite.Format(imports.Bytes())
+	}
+	return files, nil
+}
+
+// dashboardDefinitionJSON returns the JSON document that edgedelta_dashboard.definition expects,
+// the same one the provider reads into the state, or nil if the dashboard has no definition
+func dashboardDefinitionJSON(dash *edgedelta.Dashboard) ([]byte, error) {
+	combined := edgedelta.DashboardDefinition(dash, true, true)
+	if combined == nil {
+		return nil, nil
+	}
+	b, err := json.MarshalIndent(combined, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func appendImportBlock(body *hclwrite.Body, resourceType, name, id string) {
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+	})
+	block.SetAttributeValue("id", cty.StringVal(id))
+	body.AppendNewline()
+}
+
+// fileCallTokens returns the tokens of file("${path.module}/<relPath>")
+func fileCallTokens(relPath string) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte("file")},
+		{Type: hclsyntax.TokenOParen, Bytes: []byte("(")},
+		{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)},
+		{Type: hclsyntax.TokenTemplateInterp, Bytes: []byte("${")},
+		{Type: hclsyntax.TokenIdent, Bytes: []byte("path")},
+		{Type: hclsyntax.TokenDot, Bytes: []byte(".")},
+		{Type: hclsyntax.TokenIdent, Bytes: []byte("module")},
+		{Type: hclsyntax.TokenTemplateSeqEnd, Bytes: []byte("}")},
+		{Type: hclsyntax.TokenQuotedLit, Bytes: []byte("/" + relPath)},
+		{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)},
+		{Type: hclsyntax.TokenCParen, Bytes: []byte(")")},
+	}
+}
+
+// resourceNames hands out unique Terraform resource names
+type resourceNames map[string]bool
+
+func newResourceNames() resourceNames {
+	return make(resourceNames)
+}
+
+// next turns s into a valid resource name, e.g. "Prod K8s (EU)" becomes "prod_k8s_eu",
+// and appends a number if the name is already taken. fallback is used if nothing of s is left.
+func (n resourceNames) next(s, fallback string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	base := strings.TrimSuffix(b.String(), "_")
+	if base == "" {
+		base = fallback
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = fallback + "_" + base
+	}
+
+	name := base
+	for i := 2; n[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	n[name] = true
+	return name
+}
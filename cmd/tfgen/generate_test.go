@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"terraform-provider-edgedelta/edgedelta"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestGenerate(t *testing.T) {
+	configs := []*edgedelta.Config{
+		{
+			ID:           "00000000-0000-0000-0000-000000000002",
+			Tag:          "prod",
+			Content:      "version: v3\nsettings:\n  tag: prod\nnodes: []\n",
+			Environment:  edgedelta.KubernetesEnvironmentType,
+			FleetType:    edgedelta.EdgeFleetType,
+			FleetSubtype: edgedelta.GatewayFleetSubtype,
+			ClusterName:  "prod-cluster",
+			Description:  "Production \"gateway\"",
+		},
+		{
+			ID:          "00000000-0000-0000-0000-000000000001",
+			Tag:         "Prod",
+			Content:     "a: b\n",
+			Environment: edgedelta.LinuxEnvironmentType,
+		},
+	}
+	dashboards := []*edgedelta.Dashboard{
+		{
+			DashboardID:   "dash-1",
+			DashboardName: "Infrastructure Monitoring",
+			Tags:          []string{"infra"},
+			Definition:    map[string]interface{}{"panels": []interface{}{}},
+		},
+		{
+			DashboardID:   "dash-2",
+			DashboardName: "Empty",
+		},
+	}
+
+	files, err := generate(configs, dashboards)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{importsFile, configsFile, dashboardsFile} {
+		if _, diags := hclsyntax.ParseConfig(files[name], name, hcl.Pos{Line: 1, Column: 1}); diags.HasErrors() {
+			t.Errorf("%s is not valid HCL: %s\n%s", name, diags, files[name])
+		}
+	}
+
+	// Resource names are derived from the tag and deduplicated, in tag order
+	if got := string(files["configs/prod.yaml"]); got != "a: b\n" {
+		t.Errorf("unexpected content of configs/prod.yaml: %q", got)
+	}
+	if got := string(files["configs/prod_2.yaml"]); got != configs[0].Content {
+		t.Errorf("unexpected content of configs/prod_2.yaml: %q", got)
+	}
+
+	tf := string(files[configsFile])
+	for _, want := range []string{
+		`resource "edgedelta_config" "prod_2" {`,
+		`conf_id        = "00000000-0000-0000-0000-000000000002"`,
+		`config_content = file("${path.module}/configs/prod_2.yaml")`,
+		`fleet_subtype  = "Gateway"`,
+		`cluster_name   = "prod-cluster"`,
+		`description    = "Production \"gateway\""`,
+		`lifecycle_mode = "adopt"`,
+	} {
+		if !strings.Contains(tf, want) {
+			t.Errorf("expected %s to contain %q:\n%s", configsFile, want, tf)
+		}
+	}
+
+	tf = string(files[dashboardsFile])
+	for _, want := range []string{
+		`resource "edgedelta_dashboard" "infrastructure_monitoring" {`,
+		`definition     = file("${path.module}/dashboards/infrastructure_monitoring.json")`,
+		`tags           = ["infra"]`,
+		`resource "edgedelta_dashboard" "empty" {`,
+	} {
+		if !strings.Contains(tf, want) {
+			t.Errorf("expected %s to contain %q:\n%s", dashboardsFile, want, tf)
+		}
+	}
+	if _, ok := files["dashboards/empty.json"]; ok {
+		t.Error("expected no definition file for a dashboard without definition")
+	}
+	var definition map[string]interface{}
+	if err := json.Unmarshal(files["dashboards/infrastructure_monitoring.json"], &definition); err != nil {
+		t.Fatalf("invalid dashboard JSON: %v", err)
+	}
+	if _, ok := definition["definition"]; !ok {
+		t.Errorf("expected the dashboard JSON to have a definition key, got %v", definition)
+	}
+
+	tf = string(files[importsFile])
+	for _, want := range []string{
+		"to = edgedelta_config.prod\n",
+		`id = "00000000-0000-0000-0000-000000000001"`,
+		"to = edgedelta_dashboard.empty\n",
+		`id = "dash-2"`,
+	} {
+		if !strings.Contains(tf, want) {
+			t.Errorf("expected %s to contain %q:\n%s", importsFile, want, tf)
+		}
+	}
+}
+
+func TestGenerate_Empty(t *testing.T) {
+	files, err := generate(nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("expected no files, got %d", len(files))
+	}
+}
+
+func TestResourceNames(t *testing.T) {
+	names := newResourceNames()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Prod K8s (EU)", want: "prod_k8s_eu"},
+		{in: "prod-k8s-eu", want: "prod_k8s_eu_2"},
+		{in: "  ", want: "config"},
+		{in: "", want: "config_2"},
+		{in: "2024 pipeline", want: "config_2024_pipeline"},
+	}
+	for _, tt := range tests {
+		if got := names.next(tt.in, "config"); got != tt.want {
+			t.Errorf("next(%q): expected %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"configs.tf":        []byte("# configs\n"),
+		"configs/prod.yaml": []byte("a: b\n"),
+	}
+	if err := writeFiles(dir, files, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "configs", "prod.yaml"))
+	if err != nil || string(b) != "a: b\n" {
+		t.Fatalf("unexpected content %q: %v", b, err)
+	}
+
+	if err := writeFiles(dir, files, false); err == nil {
+		t.Error("expected an error when the files already exist")
+	}
+	if err := writeFiles(dir, files, true); err != nil {
+		t.Errorf("unexpected error with force: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Command tfgen generates the Terraform configuration for every config and dashboard of an
+// Edge Delta organization, so that the whole organization can be imported in one step.
+//
+// It reads the credentials like the provider does without a provider block, from the
+// EDGEDELTA_* environment variables or a credentials file profile, and writes import blocks
+// (Terraform 1.5+), resources, side-car YAML config contents and dashboard JSON files:
+//
+//	go run ./cmd/tfgen -out ./edgedelta
+//	cd ./edgedelta && terraform plan
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"terraform-provider-edgedelta/edgedelta"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "tfgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	out := flag.String("out", ".", "directory to write the generated files to")
+	profile := flag.String("profile", "", "credentials file profile, defaults to $"+edgedelta.ProfileEnvVar+" or \"default\"")
+	credentialsFile := flag.String("credentials-file", "", "credentials file, defaults to $"+edgedelta.CredentialsFileEnvVar+" or ~/.edgedelta/credentials")
+	skipConfigs := flag.Bool("skip-configs", false, "don't generate edgedelta_config resources")
+	skipDashboards := flag.Bool("skip-dashboards", false, "don't generate edgedelta_dashboard resources")
+	force := flag.Bool("force", false, "overwrite existing files")
+	maxRetries := flag.Int("max-retries", 3, "maximum number of retries for failed API requests, 0 disables retries")
+	retryMaxWait := flag.Duration("retry-max-wait", 30*time.Second, "maximum time to wait between two retries")
+	flag.Parse()
+
+	if *maxRetries < 0 {
+		return fmt.Errorf("-max-retries must not be negative, got %d", *maxRetries)
+	}
+	if *retryMaxWait <= 0 {
+		return fmt.Errorf("-retry-max-wait must be positive, got %s", *retryMaxWait)
+	}
+	client, err := edgedelta.NewAPIClientFromEnvironment(*profile, *credentialsFile, edgedelta.APIClientOptions{
+		MaxRetries:   *maxRetries,
+		RetryMaxWait: *retryMaxWait,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var configs []*edgedelta.Config
+	if !*skipConfigs {
+		if configs, err = client.GetAllConfigs(ctx); err != nil {
+			return fmt.Errorf("could not get the configs from API: %s", err)
+		}
+	}
+	var dashboards []*edgedelta.Dashboard
+	if !*skipDashboards {
+		if dashboards, err = client.GetAllDashboards(ctx); err != nil {
+			return fmt.Errorf("could not get the dashboards from API: %s", err)
+		}
+	}
+
+	files, err := generate(configs, dashboards)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("Nothing to generate, the organization has no configs or dashboards")
+		return nil
+	}
+	if err := writeFiles(*out, files, *force); err != nil {
+		return err
+	}
+	fmt.Printf("Generated %d configs and %d dashboards in %s\n", len(configs), len(dashboards), *out)
+	return nil
+}
+
+// writeFiles writes files below dir. Unless force is set, nothing is written if any of the
+// files already exists.
+func writeFiles(dir string, files map[string][]byte, force bool) error {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	if !force {
+		for _, p := range paths {
+			full := filepath.Join(dir, filepath.FromSlash(p))
+			if _, err := os.Stat(full); err == nil {
+				return fmt.Errorf("%s already exists, use -force to overwrite it", full)
+			}
+		}
+	}
+	for _, p := range paths {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(full, files[p], 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
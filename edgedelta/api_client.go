@@ -2,12 +2,17 @@ package edgedelta
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,26 +23,203 @@ func validateUUID(val string) bool {
 	return err == nil
 }
 
+// Retry defaults used when the corresponding APIClient fields are not set
+const (
+	defaultRetryWaitMin = 1 * time.Second
+	defaultRetryMaxWait = 30 * time.Second
+)
+
+// Connection pool defaults used when the corresponding APIClientOptions fields are not set
+const (
+	defaultMaxIdleConns = 10
+	defaultHTTPTimeout  = 60 * time.Second
+)
+
+// APIClient is safe for concurrent use. It should be created with NewAPIClient and shared,
+// so that all requests go through the same connection pool.
 type APIClient struct {
 	OrgID      string
 	APIBaseURL string
 	apiSecret  string
 	cl         *http.Client
+	clOnce     sync.Once
+	// sem limits the number of concurrent requests, nil means no limit
+	sem chan struct{}
+
+	// MaxRetries is the number of times a failed request is retried. Zero disables retries.
+	MaxRetries int
+	// RetryMaxWait caps the wait between two attempts, including waits requested via Retry-After
+	RetryMaxWait time.Duration
+	// retryWaitMin is the base of the exponential backoff
+	retryWaitMin time.Duration
+}
+
+// APIClientOptions tunes the retries and the connection pool of an APIClient. Zero values select the defaults.
+type APIClientOptions struct {
+	MaxRetries   int
+	RetryMaxWait time.Duration
+	// MaxIdleConns is the number of keep-alive connections kept open to the API
+	MaxIdleConns int
+	// MaxConnsPerHost limits the number of open connections to the API, 0 means no limit
+	MaxConnsPerHost int
+	// Parallelism limits the number of concurrent requests, 0 means no limit
+	Parallelism int
+}
+
+// NewAPIClient returns an APIClient for the given organization with its own connection pool
+func NewAPIClient(orgID, apiBaseURL, apiSecret string, opts APIClientOptions) *APIClient {
+	cli := &APIClient{
+		OrgID:        orgID,
+		APIBaseURL:   apiBaseURL,
+		apiSecret:    apiSecret,
+		cl:           newHTTPClient(opts),
+		MaxRetries:   opts.MaxRetries,
+		RetryMaxWait: opts.RetryMaxWait,
+	}
+	if opts.Parallelism > 0 {
+		cli.sem = make(chan struct{}, opts.Parallelism)
+	}
+	return cli
 }
 
-func (cli *APIClient) initializeHTTPClient() {
+func newHTTPClient(opts APIClientOptions) *http.Client {
+	maxIdleConns := opts.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
 	t := http.DefaultTransport.(*http.Transport).Clone()
-	t.MaxIdleConns = 1
-	t.MaxConnsPerHost = 1
-	t.MaxIdleConnsPerHost = 1
+	t.MaxIdleConns = maxIdleConns
+	t.MaxIdleConnsPerHost = maxIdleConns
+	t.MaxConnsPerHost = opts.MaxConnsPerHost
 
-	cli.cl = &http.Client{
-		Timeout:   60 * time.Second,
+	return &http.Client{
+		Timeout:   defaultHTTPTimeout,
 		Transport: t,
 	}
 }
 
-func (cli *APIClient) doRequest(entityName string, entityID string, method string, checkOKResp bool, checkNilBody bool, bodyObj interface{}) ([]byte, int, error) {
+// httpClient returns the http client of cli, creating one with the default options for
+// clients that were not created with NewAPIClient
+func (cli *APIClient) httpClient() *http.Client {
+	cli.clOnce.Do(func() {
+		if cli.cl == nil {
+			cli.cl = newHTTPClient(APIClientOptions{})
+		}
+	})
+	return cli.cl
+}
+
+// acquire blocks until the request may be sent according to the parallelism limit.
+// The returned function releases the slot.
+func (cli *APIClient) acquire(ctx context.Context) (func(), error) {
+	if cli.sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case cli.sem <- struct{}{}:
+		return func() { <-cli.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// isIdempotentMethod reports whether requests with the given HTTP method can be safely
+// retried after a transport error or a 5xx response
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetryStatus reports whether a response with the given status code should be retried.
+// 429 means the request was rejected before being processed, so it is retried for every method.
+func shouldRetryStatus(statusCode int, idempotent bool) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent && statusCode >= 500 && statusCode != http.StatusNotImplemented
+}
+
+// retryWait returns how long to wait before the next attempt. A Retry-After header on resp is
+// honored, otherwise the wait grows exponentially with attempt and is jittered. Both are capped at RetryMaxWait.
+func (cli *APIClient) retryWait(attempt int, resp *http.Response) time.Duration {
+	maxWait := cli.RetryMaxWait
+	if maxWait <= 0 {
+		maxWait = defaultRetryMaxWait
+	}
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if wait > maxWait {
+				return maxWait
+			}
+			return wait
+		}
+	}
+	minWait := cli.retryWaitMin
+	if minWait <= 0 {
+		minWait = defaultRetryWaitMin
+	}
+	wait := minWait << uint(attempt)
+	if wait <= 0 || wait > maxWait {
+		wait = maxWait
+	}
+	// Equal jitter: wait somewhere between half and the full backoff
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter parses a Retry-After header value, given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		wait := t.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleepWithContext waits for d unless ctx is done first. It returns an error right away,
+// without waiting, if ctx has a deadline that would pass before d elapses.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return fmt.Errorf("retry wait of %s exceeds the operation deadline", d)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (cli *APIClient) doRequest(ctx context.Context, entityName string, entityID string, method string, checkOKResp bool, checkNilBody bool, bodyObj interface{}) ([]byte, int, error) {
+	return cli.do(ctx, entityName, entityID, method, isIdempotentMethod(method), checkOKResp, checkNilBody, bodyObj)
+}
+
+// doIdempotentRequest is like doRequest, but retries transport errors and 5xx responses for
+// any HTTP method. It's meant for endpoints that are idempotent although they aren't called
+// with an idempotent method, such as deploying the same version again.
+func (cli *APIClient) doIdempotentRequest(ctx context.Context, entityName string, entityID string, method string, checkOKResp bool, checkNilBody bool, bodyObj interface{}) ([]byte, int, error) {
+	return cli.do(ctx, entityName, entityID, method, true, checkOKResp, checkNilBody, bodyObj)
+}
+
+func (cli *APIClient) do(ctx context.Context, entityName string, entityID string, method string, idempotent bool, checkOKResp bool, checkNilBody bool, bodyObj interface{}) ([]byte, int, error) {
 	var baseURL *url.URL
 	var err error
 
@@ -49,47 +231,76 @@ func (cli *APIClient) doRequest(entityName string, entityID string, method strin
 	if err != nil {
 		return nil, 0, fmt.Errorf("url parsing error: %v (base url was '%s')", err, cli.APIBaseURL)
 	}
-	var d io.Reader = nil
+	var db []byte
 	if bodyObj != nil {
-		db, err := json.Marshal(bodyObj)
+		db, err = json.Marshal(bodyObj)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to marshal the config object: %v", err)
 		}
-		d = bytes.NewBuffer(db)
 	}
-	req, err := http.NewRequest(method, baseURL.String(), d)
-	if err != nil {
-		return nil, 0, fmt.Errorf("http request wrapper error: %v (base url was '%s')", err, cli.APIBaseURL)
-	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("X-ED-API-Token", cli.apiSecret)
-	resp, err := cli.cl.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to do '%s %s'. error: %v", req.Method, req.URL.RequestURI(), err)
-	}
-	defer func() {
+	for attempt := 0; ; attempt++ {
+		var d io.Reader = nil
+		if db != nil {
+			d = bytes.NewReader(db)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, baseURL.String(), d)
+		if err != nil {
+			return nil, 0, fmt.Errorf("http request wrapper error: %v (base url was '%s')", err, cli.APIBaseURL)
+		}
+		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add("X-ED-API-Token", cli.apiSecret)
+		release, err := cli.acquire(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to do '%s %s'. error: %w", req.Method, req.URL.RequestURI(), err)
+		}
+		resp, err := cli.httpClient().Do(req)
+		if err != nil {
+			release()
+			if idempotent && attempt < cli.MaxRetries {
+				wait := cli.retryWait(attempt, nil)
+				log.Printf("[WARN] '%s %s' failed: %v, retrying in %s (attempt %d/%d)", req.Method, req.URL.RequestURI(), err, wait, attempt+1, cli.MaxRetries)
+				if sleepWithContext(ctx, wait) == nil {
+					continue
+				}
+			}
+			return nil, 0, fmt.Errorf("failed to do '%s %s'. error: %w", req.Method, req.URL.RequestURI(), err)
+		}
+		body, err := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-	}()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to read response body from '%s'. err: %v", req.URL.RequestURI(), err)
-	}
-	if checkOKResp && (200 > resp.StatusCode || resp.StatusCode > 299) {
-		return nil, 0, fmt.Errorf("got non OK http status from: %s %s, status: %v, response: %q", req.Method, req.URL.RequestURI(), resp.StatusCode, string(body))
-	}
-	if checkNilBody && (strings.TrimSpace(string(body)) == "null") {
-		return nil, 0, fmt.Errorf("API returned null response body from: %s, status: %v, response: %q", req.URL.RequestURI(), resp.StatusCode, string(body))
+		release()
+		if err != nil {
+			if idempotent && attempt < cli.MaxRetries {
+				wait := cli.retryWait(attempt, nil)
+				log.Printf("[WARN] failed to read response body from '%s': %v, retrying in %s (attempt %d/%d)", req.URL.RequestURI(), err, wait, attempt+1, cli.MaxRetries)
+				if sleepWithContext(ctx, wait) == nil {
+					continue
+				}
+			}
+			return nil, 0, fmt.Errorf("failed to read response body from '%s'. err: %w", req.URL.RequestURI(), err)
+		}
+		if shouldRetryStatus(resp.StatusCode, idempotent) && attempt < cli.MaxRetries {
+			wait := cli.retryWait(attempt, resp)
+			log.Printf("[WARN] got http status %d from '%s %s', retrying in %s (attempt %d/%d)", resp.StatusCode, req.Method, req.URL.RequestURI(), wait, attempt+1, cli.MaxRetries)
+			if sleepWithContext(ctx, wait) == nil {
+				continue
+			}
+		}
+		if checkOKResp && (200 > resp.StatusCode || resp.StatusCode > 299) {
+			return nil, resp.StatusCode, newAPIError(req, resp, body)
+		}
+		if checkNilBody && (strings.TrimSpace(string(body)) == "null") {
+			return nil, resp.StatusCode, fmt.Errorf("API returned null response body from: %s, status: %v, response: %q", req.URL.RequestURI(), resp.StatusCode, string(body))
 
+		}
+		return body, resp.StatusCode, nil
 	}
-	return body, resp.StatusCode, nil
 }
 
-func (cli *APIClient) GetConfigWithID(configID string) (*GetConfigResponse, error) {
+func (cli *APIClient) GetConfigWithID(ctx context.Context, configID string) (*GetConfigResponse, error) {
 	if ok := validateUUID(configID); !ok {
 		return nil, fmt.Errorf("failed to validate the config ID: '%s'", configID)
 	}
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("confs", configID, http.MethodGet, true, true, nil)
+	b, _, err := cli.doRequest(ctx, "confs", configID, http.MethodGet, true, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -100,9 +311,8 @@ func (cli *APIClient) GetConfigWithID(configID string) (*GetConfigResponse, erro
 	return &responseData, nil
 }
 
-func (cli *APIClient) GetAllConfigs() ([]*Config, error) {
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("confs", "", http.MethodGet, true, true, nil)
+func (cli *APIClient) GetAllConfigs(ctx context.Context) ([]*Config, error) {
+	b, _, err := cli.doRequest(ctx, "confs", "", http.MethodGet, true, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -113,9 +323,8 @@ func (cli *APIClient) GetAllConfigs() ([]*Config, error) {
 	return responseData, nil
 }
 
-func (cli *APIClient) CreateConfig(configObject Config) (*CreateConfigResponse, error) {
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("confs", "", http.MethodPost, true, true, configObject)
+func (cli *APIClient) CreateConfig(ctx context.Context, configObject Config) (*CreateConfigResponse, error) {
+	b, _, err := cli.doRequest(ctx, "confs", "", http.MethodPost, true, true, configObject)
 	if err != nil {
 		return nil, err
 	}
@@ -126,12 +335,11 @@ func (cli *APIClient) CreateConfig(configObject Config) (*CreateConfigResponse,
 	return &responseData, nil
 }
 
-func (cli *APIClient) UpdateConfigWithID(configID string, configObject Config) (*UpdateConfigResponse, error) {
+func (cli *APIClient) UpdateConfigWithID(ctx context.Context, configID string, configObject Config) (*UpdateConfigResponse, error) {
 	if ok := validateUUID(configID); !ok {
 		return nil, fmt.Errorf("failed to validate the config ID: '%s'", configID)
 	}
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("confs", configID, http.MethodPut, true, true, configObject)
+	b, _, err := cli.doRequest(ctx, "confs", configID, http.MethodPut, true, true, configObject)
 	if err != nil {
 		return nil, err
 	}
@@ -142,12 +350,12 @@ func (cli *APIClient) UpdateConfigWithID(configID string, configObject Config) (
 	return &responseData, nil
 }
 
-func (cli *APIClient) SaveConfig(configID string, saveReq SaveRequest) (*SaveConfigResponse, error) {
+func (cli *APIClient) SaveConfig(ctx context.Context, configID string, saveReq SaveRequest) (*SaveConfigResponse, error) {
 	if ok := validateUUID(configID); !ok {
 		return nil, fmt.Errorf("failed to validate the config ID: '%s'", configID)
 	}
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("pipelines", fmt.Sprintf("%s/save", configID), http.MethodPost, true, true, saveReq)
+	// Every save creates a history version, so a save that may have reached the API isn't retried
+	b, _, err := cli.doRequest(ctx, "pipelines", fmt.Sprintf("%s/save", configID), http.MethodPost, true, true, saveReq)
 	if err != nil {
 		return nil, err
 	}
@@ -158,12 +366,11 @@ func (cli *APIClient) SaveConfig(configID string, saveReq SaveRequest) (*SaveCon
 	return &responseData, nil
 }
 
-func (cli *APIClient) DeployConfig(configID string, version int64) (*DeployConfigResponse, error) {
+func (cli *APIClient) DeployConfig(ctx context.Context, configID string, version int64) (*DeployConfigResponse, error) {
 	if ok := validateUUID(configID); !ok {
 		return nil, fmt.Errorf("failed to validate the config ID: '%s'", configID)
 	}
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("pipelines", fmt.Sprintf("%s/deploy/%d", configID, version), http.MethodPost, true, true, nil)
+	b, _, err := cli.doIdempotentRequest(ctx, "pipelines", fmt.Sprintf("%s/deploy/%d", configID, version), http.MethodPost, true, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -174,18 +381,26 @@ func (cli *APIClient) DeployConfig(configID string, version int64) (*DeployConfi
 	return &responseData, nil
 }
 
-func (cli *APIClient) GetLatestConfigHistoryVersion(configID string) (int64, error) {
+// GetConfigHistory returns the saved versions of a config, sorted by timestamp descending
+func (cli *APIClient) GetConfigHistory(ctx context.Context, configID string) ([]ConfigHistory, error) {
 	if ok := validateUUID(configID); !ok {
-		return 0, fmt.Errorf("failed to validate the config ID: '%s'", configID)
+		return nil, fmt.Errorf("failed to validate the config ID: '%s'", configID)
 	}
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("pipelines", fmt.Sprintf("%s/history", configID), http.MethodGet, true, true, nil)
+	b, _, err := cli.doRequest(ctx, "pipelines", fmt.Sprintf("%s/history", configID), http.MethodGet, true, true, nil)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	var histories []ConfigHistory
 	if err := json.Unmarshal(b, &histories); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal the response body: %s", err)
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return histories, nil
+}
+
+func (cli *APIClient) GetLatestConfigHistoryVersion(ctx context.Context, configID string) (int64, error) {
+	histories, err := cli.GetConfigHistory(ctx, configID)
+	if err != nil {
+		return 0, err
 	}
 	if len(histories) == 0 {
 		return 0, fmt.Errorf("no config history found for config ID: '%s'", configID)
@@ -194,12 +409,27 @@ func (cli *APIClient) GetLatestConfigHistoryVersion(configID string) (int64, err
 	return histories[0].Timestamp, nil
 }
 
-func (cli *APIClient) DeleteConfigWithID(configID string) error {
+// GetPipelineStatus returns the deployed version of a config and the status of the agents running it
+func (cli *APIClient) GetPipelineStatus(ctx context.Context, configID string) (*PipelineStatus, error) {
+	if ok := validateUUID(configID); !ok {
+		return nil, fmt.Errorf("failed to validate the config ID: '%s'", configID)
+	}
+	b, _, err := cli.doRequest(ctx, "pipelines", fmt.Sprintf("%s/status", configID), http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData PipelineStatus
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+func (cli *APIClient) DeleteConfigWithID(ctx context.Context, configID string) error {
 	if ok := validateUUID(configID); !ok {
 		return fmt.Errorf("failed to validate the config ID: '%s'", configID)
 	}
-	cli.initializeHTTPClient()
-	_, _, err := cli.doRequest("confs", configID, http.MethodDelete, true, true, nil)
+	_, _, err := cli.doRequest(ctx, "confs", configID, http.MethodDelete, true, true, nil)
 	if err != nil {
 		return err
 	}
@@ -209,12 +439,11 @@ func (cli *APIClient) DeleteConfigWithID(configID string) error {
 // Dashboard API methods
 
 // GetDashboard retrieves a single dashboard by ID
-func (cli *APIClient) GetDashboard(dashboardID string) (*GetDashboardResponse, error) {
+func (cli *APIClient) GetDashboard(ctx context.Context, dashboardID string) (*GetDashboardResponse, error) {
 	if ok := validateUUID(dashboardID); !ok {
 		return nil, fmt.Errorf("failed to validate the dashboard ID: '%s'", dashboardID)
 	}
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("dashboards", dashboardID, http.MethodGet, true, true, nil)
+	b, _, err := cli.doRequest(ctx, "dashboards", dashboardID, http.MethodGet, true, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -226,9 +455,8 @@ func (cli *APIClient) GetDashboard(dashboardID string) (*GetDashboardResponse, e
 }
 
 // GetAllDashboards retrieves all dashboards for the organization (used for import)
-func (cli *APIClient) GetAllDashboards() ([]*Dashboard, error) {
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("dashboards", "", http.MethodGet, true, true, nil)
+func (cli *APIClient) GetAllDashboards(ctx context.Context) ([]*Dashboard, error) {
+	b, _, err := cli.doRequest(ctx, "dashboards", "", http.MethodGet, true, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -240,9 +468,8 @@ func (cli *APIClient) GetAllDashboards() ([]*Dashboard, error) {
 }
 
 // CreateDashboard creates a new dashboard
-func (cli *APIClient) CreateDashboard(dashboard *Dashboard) (*CreateDashboardResponse, error) {
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("dashboards", "", http.MethodPost, true, true, dashboard)
+func (cli *APIClient) CreateDashboard(ctx context.Context, dashboard *Dashboard) (*CreateDashboardResponse, error) {
+	b, _, err := cli.doRequest(ctx, "dashboards", "", http.MethodPost, true, true, dashboard)
 	if err != nil {
 		return nil, err
 	}
@@ -254,12 +481,11 @@ func (cli *APIClient) CreateDashboard(dashboard *Dashboard) (*CreateDashboardRes
 }
 
 // UpdateDashboard updates an existing dashboard
-func (cli *APIClient) UpdateDashboard(dashboardID string, dashboard *Dashboard) (*UpdateDashboardResponse, error) {
+func (cli *APIClient) UpdateDashboard(ctx context.Context, dashboardID string, dashboard *Dashboard) (*UpdateDashboardResponse, error) {
 	if ok := validateUUID(dashboardID); !ok {
 		return nil, fmt.Errorf("failed to validate the dashboard ID: '%s'", dashboardID)
 	}
-	cli.initializeHTTPClient()
-	b, _, err := cli.doRequest("dashboards", dashboardID, http.MethodPut, true, true, dashboard)
+	b, _, err := cli.doRequest(ctx, "dashboards", dashboardID, http.MethodPut, true, true, dashboard)
 	if err != nil {
 		return nil, err
 	}
@@ -271,12 +497,476 @@ func (cli *APIClient) UpdateDashboard(dashboardID string, dashboard *Dashboard)
 }
 
 // DeleteDashboard deletes a dashboard by ID
-func (cli *APIClient) DeleteDashboard(dashboardID string) error {
+func (cli *APIClient) DeleteDashboard(ctx context.Context, dashboardID string) error {
 	if ok := validateUUID(dashboardID); !ok {
 		return fmt.Errorf("failed to validate the dashboard ID: '%s'", dashboardID)
 	}
-	cli.initializeHTTPClient()
-	_, _, err := cli.doRequest("dashboards", dashboardID, http.MethodDelete, true, false, nil)
+	_, _, err := cli.doRequest(ctx, "dashboards", dashboardID, http.MethodDelete, true, false, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// dashboardAccessAttempts is how many times UpdateDashboardAccesses applies its change before
+// giving up on a dashboard that keeps changing
+const dashboardAccessAttempts = 3
+
+// GetDashboardAccesses returns the resource accesses of a dashboard
+func (cli *APIClient) GetDashboardAccesses(ctx context.Context, dashboardID string) ([]map[string]interface{}, error) {
+	resp, err := cli.GetDashboard(ctx, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ResourceAccesses, nil
+}
+
+// UpdateDashboardAccesses applies modify to the resource accesses of a dashboard and saves them.
+// The API only replaces whole dashboards, so the rest of the dashboard is sent back as it was read,
+// with its updated timestamp as a precondition: if the dashboard changed in the meantime, the API
+// answers with a conflict and the change is applied again to the new version of the dashboard.
+func (cli *APIClient) UpdateDashboardAccesses(ctx context.Context, dashboardID string, modify func([]map[string]interface{}) ([]map[string]interface{}, error)) error {
+	for attempt := 1; ; attempt++ {
+		resp, err := cli.GetDashboard(ctx, dashboardID)
+		if err != nil {
+			return err
+		}
+		dashboard := Dashboard(*resp)
+		accesses, err := modify(dashboard.ResourceAccesses)
+		if err != nil {
+			return err
+		}
+		dashboard.ResourceAccesses = accesses
+		_, err = cli.UpdateDashboard(ctx, dashboardID, &dashboard)
+		if err == nil || !IsConflict(err) || attempt == dashboardAccessAttempts {
+			return err
+		}
+		log.Printf("[WARN] dashboard '%s' changed while its accesses were updated, retrying (attempt %d/%d)", dashboardID, attempt, dashboardAccessAttempts)
+	}
+}
+
+// Monitor API methods
+
+// GetMonitor retrieves a single monitor by ID
+func (cli *APIClient) GetMonitor(ctx context.Context, monitorID string) (*GetMonitorResponse, error) {
+	if ok := validateUUID(monitorID); !ok {
+		return nil, fmt.Errorf("failed to validate the monitor ID: '%s'", monitorID)
+	}
+	b, _, err := cli.doRequest(ctx, "monitors", monitorID, http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData GetMonitorResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// GetAllMonitors retrieves all monitors for the organization (used for import)
+func (cli *APIClient) GetAllMonitors(ctx context.Context) ([]*Monitor, error) {
+	b, _, err := cli.doRequest(ctx, "monitors", "", http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData []*Monitor
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return responseData, nil
+}
+
+// CreateMonitor creates a new monitor
+func (cli *APIClient) CreateMonitor(ctx context.Context, monitor *Monitor) (*CreateMonitorResponse, error) {
+	b, _, err := cli.doRequest(ctx, "monitors", "", http.MethodPost, true, true, monitor)
+	if err != nil {
+		return nil, err
+	}
+	var responseData CreateMonitorResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// UpdateMonitor updates an existing monitor
+func (cli *APIClient) UpdateMonitor(ctx context.Context, monitorID string, monitor *Monitor) (*UpdateMonitorResponse, error) {
+	if ok := validateUUID(monitorID); !ok {
+		return nil, fmt.Errorf("failed to validate the monitor ID: '%s'", monitorID)
+	}
+	b, _, err := cli.doRequest(ctx, "monitors", monitorID, http.MethodPut, true, true, monitor)
+	if err != nil {
+		return nil, err
+	}
+	var responseData UpdateMonitorResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// DeleteMonitor deletes a monitor by ID
+func (cli *APIClient) DeleteMonitor(ctx context.Context, monitorID string) error {
+	if ok := validateUUID(monitorID); !ok {
+		return fmt.Errorf("failed to validate the monitor ID: '%s'", monitorID)
+	}
+	_, _, err := cli.doRequest(ctx, "monitors", monitorID, http.MethodDelete, true, false, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Notification channel API methods
+
+// GetNotificationChannel retrieves a single notification channel by ID
+func (cli *APIClient) GetNotificationChannel(ctx context.Context, channelID string) (*GetNotificationChannelResponse, error) {
+	if ok := validateUUID(channelID); !ok {
+		return nil, fmt.Errorf("failed to validate the notification channel ID: '%s'", channelID)
+	}
+	b, _, err := cli.doRequest(ctx, "notification_channels", channelID, http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData GetNotificationChannelResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// GetAllNotificationChannels retrieves all notification channels for the organization (used for import)
+func (cli *APIClient) GetAllNotificationChannels(ctx context.Context) ([]*NotificationChannel, error) {
+	b, _, err := cli.doRequest(ctx, "notification_channels", "", http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData []*NotificationChannel
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return responseData, nil
+}
+
+// CreateNotificationChannel creates a new notification channel
+func (cli *APIClient) CreateNotificationChannel(ctx context.Context, channel *NotificationChannel) (*CreateNotificationChannelResponse, error) {
+	b, _, err := cli.doRequest(ctx, "notification_channels", "", http.MethodPost, true, true, channel)
+	if err != nil {
+		return nil, err
+	}
+	var responseData CreateNotificationChannelResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// UpdateNotificationChannel updates an existing notification channel
+func (cli *APIClient) UpdateNotificationChannel(ctx context.Context, channelID string, channel *NotificationChannel) (*UpdateNotificationChannelResponse, error) {
+	if ok := validateUUID(channelID); !ok {
+		return nil, fmt.Errorf("failed to validate the notification channel ID: '%s'", channelID)
+	}
+	b, _, err := cli.doRequest(ctx, "notification_channels", channelID, http.MethodPut, true, true, channel)
+	if err != nil {
+		return nil, err
+	}
+	var responseData UpdateNotificationChannelResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// DeleteNotificationChannel deletes a notification channel by ID
+func (cli *APIClient) DeleteNotificationChannel(ctx context.Context, channelID string) error {
+	if ok := validateUUID(channelID); !ok {
+		return fmt.Errorf("failed to validate the notification channel ID: '%s'", channelID)
+	}
+	_, _, err := cli.doRequest(ctx, "notification_channels", channelID, http.MethodDelete, true, false, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// API token API methods
+
+// GetAPIToken retrieves the metadata of a single API token by ID, without the secret
+func (cli *APIClient) GetAPIToken(ctx context.Context, tokenID string) (*GetAPITokenResponse, error) {
+	if ok := validateUUID(tokenID); !ok {
+		return nil, fmt.Errorf("failed to validate the API token ID: '%s'", tokenID)
+	}
+	b, _, err := cli.doRequest(ctx, "api_tokens", tokenID, http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData GetAPITokenResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// GetAllAPITokens retrieves the metadata of all API tokens for the organization, without the secrets
+func (cli *APIClient) GetAllAPITokens(ctx context.Context) ([]*APIToken, error) {
+	b, _, err := cli.doRequest(ctx, "api_tokens", "", http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData []*APIToken
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return responseData, nil
+}
+
+// CreateAPIToken creates a new API token, the response is the only one containing the secret
+func (cli *APIClient) CreateAPIToken(ctx context.Context, token *APIToken) (*CreateAPITokenResponse, error) {
+	b, _, err := cli.doRequest(ctx, "api_tokens", "", http.MethodPost, true, true, token)
+	if err != nil {
+		return nil, err
+	}
+	var responseData CreateAPITokenResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// DeleteAPIToken revokes an API token by ID
+func (cli *APIClient) DeleteAPIToken(ctx context.Context, tokenID string) error {
+	if ok := validateUUID(tokenID); !ok {
+		return fmt.Errorf("failed to validate the API token ID: '%s'", tokenID)
+	}
+	_, _, err := cli.doRequest(ctx, "api_tokens", tokenID, http.MethodDelete, true, false, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// User API methods
+
+// GetUser retrieves a single user by ID
+func (cli *APIClient) GetUser(ctx context.Context, userID string) (*GetUserResponse, error) {
+	if ok := validateUUID(userID); !ok {
+		return nil, fmt.Errorf("failed to validate the user ID: '%s'", userID)
+	}
+	b, _, err := cli.doRequest(ctx, "users", userID, http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData GetUserResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// GetAllUsers retrieves all users of the organization (used for import)
+func (cli *APIClient) GetAllUsers(ctx context.Context) ([]*User, error) {
+	b, _, err := cli.doRequest(ctx, "users", "", http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData []*User
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return responseData, nil
+}
+
+// InviteUser invites a user to the organization by email
+func (cli *APIClient) InviteUser(ctx context.Context, user *User) (*InviteUserResponse, error) {
+	b, _, err := cli.doRequest(ctx, "users", "", http.MethodPost, true, true, user)
+	if err != nil {
+		return nil, err
+	}
+	var responseData InviteUserResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// UpdateUser updates an existing user
+func (cli *APIClient) UpdateUser(ctx context.Context, userID string, user *User) (*UpdateUserResponse, error) {
+	if ok := validateUUID(userID); !ok {
+		return nil, fmt.Errorf("failed to validate the user ID: '%s'", userID)
+	}
+	b, _, err := cli.doRequest(ctx, "users", userID, http.MethodPut, true, true, user)
+	if err != nil {
+		return nil, err
+	}
+	var responseData UpdateUserResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// RevokeUser removes the access of a user to the organization, the user record is kept
+func (cli *APIClient) RevokeUser(ctx context.Context, userID string) error {
+	if ok := validateUUID(userID); !ok {
+		return fmt.Errorf("failed to validate the user ID: '%s'", userID)
+	}
+	_, _, err := cli.doIdempotentRequest(ctx, "users", fmt.Sprintf("%s/revoke", userID), http.MethodPost, true, false, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteUser deletes a user and its record from the organization
+func (cli *APIClient) DeleteUser(ctx context.Context, userID string) error {
+	if ok := validateUUID(userID); !ok {
+		return fmt.Errorf("failed to validate the user ID: '%s'", userID)
+	}
+	_, _, err := cli.doRequest(ctx, "users", userID, http.MethodDelete, true, false, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Team API methods
+
+// GetTeam retrieves a single team by ID
+func (cli *APIClient) GetTeam(ctx context.Context, teamID string) (*GetTeamResponse, error) {
+	if ok := validateUUID(teamID); !ok {
+		return nil, fmt.Errorf("failed to validate the team ID: '%s'", teamID)
+	}
+	b, _, err := cli.doRequest(ctx, "teams", teamID, http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData GetTeamResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// GetAllTeams retrieves all teams of the organization (used for import)
+func (cli *APIClient) GetAllTeams(ctx context.Context) ([]*Team, error) {
+	b, _, err := cli.doRequest(ctx, "teams", "", http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData []*Team
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return responseData, nil
+}
+
+// CreateTeam creates a new team
+func (cli *APIClient) CreateTeam(ctx context.Context, team *Team) (*CreateTeamResponse, error) {
+	b, _, err := cli.doRequest(ctx, "teams", "", http.MethodPost, true, true, team)
+	if err != nil {
+		return nil, err
+	}
+	var responseData CreateTeamResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// UpdateTeam updates an existing team
+func (cli *APIClient) UpdateTeam(ctx context.Context, teamID string, team *Team) (*UpdateTeamResponse, error) {
+	if ok := validateUUID(teamID); !ok {
+		return nil, fmt.Errorf("failed to validate the team ID: '%s'", teamID)
+	}
+	b, _, err := cli.doRequest(ctx, "teams", teamID, http.MethodPut, true, true, team)
+	if err != nil {
+		return nil, err
+	}
+	var responseData UpdateTeamResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// DeleteTeam deletes a team by ID, its members stay in the organization
+func (cli *APIClient) DeleteTeam(ctx context.Context, teamID string) error {
+	if ok := validateUUID(teamID); !ok {
+		return fmt.Errorf("failed to validate the team ID: '%s'", teamID)
+	}
+	_, _, err := cli.doRequest(ctx, "teams", teamID, http.MethodDelete, true, false, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Role API methods
+
+// GetRole retrieves a single role by ID
+func (cli *APIClient) GetRole(ctx context.Context, roleID string) (*GetRoleResponse, error) {
+	if ok := validateUUID(roleID); !ok {
+		return nil, fmt.Errorf("failed to validate the role ID: '%s'", roleID)
+	}
+	b, _, err := cli.doRequest(ctx, "roles", roleID, http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData GetRoleResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// GetAllRoles retrieves all roles of the organization (used for import)
+func (cli *APIClient) GetAllRoles(ctx context.Context) ([]*Role, error) {
+	b, _, err := cli.doRequest(ctx, "roles", "", http.MethodGet, true, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	var responseData []*Role
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return responseData, nil
+}
+
+// CreateRole creates a new custom role
+func (cli *APIClient) CreateRole(ctx context.Context, role *Role) (*CreateRoleResponse, error) {
+	b, _, err := cli.doRequest(ctx, "roles", "", http.MethodPost, true, true, role)
+	if err != nil {
+		return nil, err
+	}
+	var responseData CreateRoleResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// UpdateRole updates an existing role
+func (cli *APIClient) UpdateRole(ctx context.Context, roleID string, role *Role) (*UpdateRoleResponse, error) {
+	if ok := validateUUID(roleID); !ok {
+		return nil, fmt.Errorf("failed to validate the role ID: '%s'", roleID)
+	}
+	b, _, err := cli.doRequest(ctx, "roles", roleID, http.MethodPut, true, true, role)
+	if err != nil {
+		return nil, err
+	}
+	var responseData UpdateRoleResponse
+	if err := json.Unmarshal(b, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response body: %s", err)
+	}
+	return &responseData, nil
+}
+
+// DeleteRole deletes a custom role by ID
+func (cli *APIClient) DeleteRole(ctx context.Context, roleID string) error {
+	if ok := validateUUID(roleID); !ok {
+		return fmt.Errorf("failed to validate the role ID: '%s'", roleID)
+	}
+	_, _, err := cli.doRequest(ctx, "roles", roleID, http.MethodDelete, true, false, nil)
 	if err != nil {
 		return err
 	}
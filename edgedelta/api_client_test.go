@@ -1,13 +1,19 @@
 package edgedelta
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var (
@@ -24,6 +30,14 @@ const (
 	testAPISecret   = "test-api-secret"
 	testDashboardID = "550e8400-e29b-41d4-a716-446655440000"
 	testConfigID    = "660e8400-e29b-41d4-a716-446655440001"
+	testMonitorID   = "770e8400-e29b-41d4-a716-446655440002"
+	testChannelID   = "880e8400-e29b-41d4-a716-446655440003"
+	testTokenID     = "990e8400-e29b-41d4-a716-446655440004"
+	testUserID      = "aa0e8400-e29b-41d4-a716-446655440005"
+	testOtherUserID = "aa0e8400-e29b-41d4-a716-446655440006"
+	testTeamID      = "bb0e8400-e29b-41d4-a716-446655440007"
+	testRoleID      = "cc0e8400-e29b-41d4-a716-446655440008"
+	testOtherRoleID = "cc0e8400-e29b-41d4-a716-446655440009"
 )
 
 // Helper function to create a mock server
@@ -33,11 +47,7 @@ func newMockServer(handler http.HandlerFunc) *httptest.Server {
 
 // Helper function to create an API client pointing to mock server
 func newTestClient(serverURL string) *APIClient {
-	return &APIClient{
-		OrgID:      testOrgID,
-		APIBaseURL: serverURL,
-		apiSecret:  testAPISecret,
-	}
+	return NewAPIClient(testOrgID, serverURL, testAPISecret, APIClientOptions{})
 }
 
 // =============================================================================
@@ -78,7 +88,7 @@ func TestGetDashboard(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.GetDashboard(testDashboardID)
+	result, err := client.GetDashboard(context.Background(), testDashboardID)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -101,7 +111,7 @@ func TestGetDashboard_InvalidID(t *testing.T) {
 		apiSecret:  testAPISecret,
 	}
 
-	_, err := client.GetDashboard("invalid-uuid")
+	_, err := client.GetDashboard(context.Background(), "invalid-uuid")
 	if err == nil {
 		t.Error("expected error for invalid UUID, got nil")
 	}
@@ -118,10 +128,13 @@ func TestGetDashboard_NotFound(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	_, err := client.GetDashboard(testDashboardID)
+	_, err := client.GetDashboard(context.Background(), testDashboardID)
 
 	if err == nil {
-		t.Error("expected error for 404 response, got nil")
+		t.Fatal("expected error for 404 response, got nil")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got: %v", err)
 	}
 }
 
@@ -154,7 +167,7 @@ func TestGetAllDashboards(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.GetAllDashboards()
+	result, err := client.GetAllDashboards(context.Background())
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -175,7 +188,7 @@ func TestGetAllDashboards_Empty(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.GetAllDashboards()
+	result, err := client.GetAllDashboards(context.Background())
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -228,7 +241,7 @@ func TestCreateDashboard(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.CreateDashboard(inputDashboard)
+	result, err := client.CreateDashboard(context.Background(), inputDashboard)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -272,7 +285,7 @@ func TestUpdateDashboard(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.UpdateDashboard(testDashboardID, inputDashboard)
+	result, err := client.UpdateDashboard(context.Background(), testDashboardID, inputDashboard)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -292,7 +305,7 @@ func TestUpdateDashboard_InvalidID(t *testing.T) {
 		apiSecret:  testAPISecret,
 	}
 
-	_, err := client.UpdateDashboard("invalid-uuid", &Dashboard{})
+	_, err := client.UpdateDashboard(context.Background(), "invalid-uuid", &Dashboard{})
 	if err == nil {
 		t.Error("expected error for invalid UUID, got nil")
 	}
@@ -313,7 +326,7 @@ func TestDeleteDashboard(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	err := client.DeleteDashboard(testDashboardID)
+	err := client.DeleteDashboard(context.Background(), testDashboardID)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -327,7 +340,7 @@ func TestDeleteDashboard_InvalidID(t *testing.T) {
 		apiSecret:  testAPISecret,
 	}
 
-	err := client.DeleteDashboard("invalid-uuid")
+	err := client.DeleteDashboard(context.Background(), "invalid-uuid")
 	if err == nil {
 		t.Error("expected error for invalid UUID, got nil")
 	}
@@ -341,104 +354,416 @@ func TestDeleteDashboard_NotFound(t *testing.T) {
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	err := client.DeleteDashboard(testDashboardID)
+	err := client.DeleteDashboard(context.Background(), testDashboardID)
 
 	if err == nil {
-		t.Error("expected error for 404 response, got nil")
+		t.Fatal("expected error for 404 response, got nil")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got: %v", err)
+	}
+}
+
+// newConflictingDashboardServer serves a dashboard that only accepts updates carrying its current
+// updated timestamp. The first outsideEdits GETs are each followed by an edit made outside Terraform.
+func newConflictingDashboardServer(t *testing.T, dashboard *Dashboard, outsideEdits int) *httptest.Server {
+	var mu sync.Mutex
+	version := 1
+	dashboard.Updated = "1"
+	return newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(dashboard)
+			if outsideEdits > 0 {
+				outsideEdits--
+				version++
+				dashboard.Updated = fmt.Sprint(version)
+				dashboard.ResourceAccesses = append(dashboard.ResourceAccesses, map[string]interface{}{
+					"principal_type": "user", "principal_id": fmt.Sprintf("outside-%d", version), "access_level": "viewer",
+				})
+			}
+		case http.MethodPut:
+			var updated Dashboard
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Errorf("failed to decode request body: %v", err)
+			}
+			if updated.Updated != dashboard.Updated {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error": "dashboard was modified"}`))
+				return
+			}
+			version++
+			updated.Updated = fmt.Sprint(version)
+			*dashboard = updated
+			_ = json.NewEncoder(w).Encode(dashboard)
+		}
+	})
+}
+
+func TestUpdateDashboardAccesses_Conflict(t *testing.T) {
+	dashboard := &Dashboard{DashboardID: testDashboardID, DashboardName: "Test Dashboard"}
+	server := newConflictingDashboardServer(t, dashboard, 1)
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	calls := 0
+	err := client.UpdateDashboardAccesses(context.Background(), testDashboardID, func(accesses []map[string]interface{}) ([]map[string]interface{}, error) {
+		calls++
+		return append(accesses, map[string]interface{}{"principal_type": "team", "principal_id": testTeamID, "access_level": "editor"}), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the change to be applied again after the conflict, applied %d times", calls)
+	}
+	if len(dashboard.ResourceAccesses) != 2 || dashboard.ResourceAccesses[0]["principal_id"] != "outside-2" {
+		t.Errorf("expected the access granted outside Terraform to be kept, got %v", dashboard.ResourceAccesses)
+	}
+}
+
+func TestUpdateDashboardAccesses_GivesUp(t *testing.T) {
+	dashboard := &Dashboard{DashboardID: testDashboardID, DashboardName: "Test Dashboard"}
+	server := newConflictingDashboardServer(t, dashboard, dashboardAccessAttempts)
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	err := client.UpdateDashboardAccesses(context.Background(), testDashboardID, func(accesses []map[string]interface{}) ([]map[string]interface{}, error) {
+		return append(accesses, map[string]interface{}{"principal_type": "team", "principal_id": testTeamID, "access_level": "editor"}), nil
+	})
+	if !IsConflict(err) {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+	if len(dashboard.ResourceAccesses) != dashboardAccessAttempts {
+		t.Errorf("expected only the accesses granted outside Terraform, got %v", dashboard.ResourceAccesses)
 	}
 }
 
 // =============================================================================
-// Config API Unit Tests (Mock Server)
+// Monitor API Unit Tests (Mock Server)
 // =============================================================================
 
-func TestGetConfigWithID_Mock(t *testing.T) {
-	expectedConfig := GetConfigResponse{
-		ID:          testConfigID,
-		Content:     "test: config",
-		Description: "Test config",
-		Tag:         "v1.0.0",
+func TestGetMonitor(t *testing.T) {
+	expectedMonitor := Monitor{
+		MonitorID:           testMonitorID,
+		Name:                "High error rate",
+		Type:                ThresholdMonitorType,
+		Query:               "sum(errors)",
+		Threshold:           &MonitorThreshold{Operator: ">", Value: 100},
+		EvaluationWindow:    "5m",
+		Severity:            "critical",
+		NotificationTargets: []string{"channel-1"},
+		Tags:                []string{"prod"},
 	}
 
 	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			t.Errorf("expected GET method, got %s", r.Method)
 		}
-		expectedPath := "/v1/orgs/" + testOrgID + "/confs/" + testConfigID
+		expectedPath := "/v1/orgs/" + testOrgID + "/monitors/" + testMonitorID
 		if r.URL.Path != expectedPath {
 			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(expectedConfig); err != nil {
+		if err := json.NewEncoder(w).Encode(expectedMonitor); err != nil {
 			t.Errorf("failed to encode response: %v", err)
 		}
 	})
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.GetConfigWithID(testConfigID)
+	result, err := client.GetMonitor(context.Background(), testMonitorID)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if result.ID != expectedConfig.ID {
-		t.Errorf("expected config ID %s, got %s", expectedConfig.ID, result.ID)
+	if result.MonitorID != testMonitorID {
+		t.Errorf("expected monitor ID %s, got %s", testMonitorID, result.MonitorID)
+	}
+	if result.Threshold == nil || result.Threshold.Value != 100 {
+		t.Errorf("expected a threshold of 100, got %+v", result.Threshold)
+	}
+	if result.Anomaly != nil {
+		t.Errorf("expected no anomaly condition, got %+v", result.Anomaly)
 	}
 }
 
-func TestGetConfigWithID_InvalidID(t *testing.T) {
+func TestGetMonitor_InvalidID(t *testing.T) {
+	client := &APIClient{
+		OrgID:      testOrgID,
+		APIBaseURL: "http://localhost",
+		apiSecret:  testAPISecret,
+	}
+
+	_, err := client.GetMonitor(context.Background(), "invalid-uuid")
+	if err == nil {
+		t.Fatal("expected error for invalid UUID, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to validate the monitor ID") {
+		t.Errorf("expected UUID validation error, got: %v", err)
+	}
+}
+
+func TestGetMonitor_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "monitor not found"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	_, err := client.GetMonitor(context.Background(), testMonitorID)
+
+	if err == nil {
+		t.Fatal("expected error for 404 response, got nil")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got: %v", err)
+	}
+}
+
+func TestGetAllMonitors(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/v1/orgs/" + testOrgID + "/monitors"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"monitor_id": "770e8400-e29b-41d4-a716-446655440003", "name": "Monitor 1"}, {"monitor_id": "770e8400-e29b-41d4-a716-446655440004", "name": "Monitor 2"}]`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.GetAllMonitors(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 monitors, got %d", len(result))
+	}
+	if result[1].Name != "Monitor 2" {
+		t.Errorf("expected 'Monitor 2', got %s", result[1].Name)
+	}
+}
+
+func TestCreateMonitor(t *testing.T) {
+	inputMonitor := &Monitor{
+		Name:    "Traffic anomaly",
+		Type:    AnomalyMonitorType,
+		Query:   "sum(requests)",
+		Anomaly: &MonitorAnomaly{Sensitivity: "high", Direction: "below"},
+		Muted:   true,
+	}
+
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/monitors"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		// Verify request body
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if body["type"] != "anomaly" || body["muted"] != true {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		if _, ok := body["threshold"]; ok {
+			t.Errorf("expected no threshold in the request body, got %v", body["threshold"])
+		}
+
+		response := *inputMonitor
+		response.MonitorID = testMonitorID
+		response.Creator = "user-123"
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.CreateMonitor(context.Background(), inputMonitor)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.MonitorID != testMonitorID {
+		t.Errorf("expected monitor ID %s, got %s", testMonitorID, result.MonitorID)
+	}
+	if result.Anomaly == nil || result.Anomaly.Direction != "below" {
+		t.Errorf("expected the anomaly direction to be below, got %+v", result.Anomaly)
+	}
+}
+
+func TestUpdateMonitor(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/monitors/" + testMonitorID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"monitor_id": "` + testMonitorID + `", "name": "Renamed", "updater": "user-456"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.UpdateMonitor(context.Background(), testMonitorID, &Monitor{Name: "Renamed"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Name != "Renamed" || result.Updater != "user-456" {
+		t.Errorf("unexpected monitor: %+v", result)
+	}
+}
+
+func TestUpdateMonitor_InvalidID(t *testing.T) {
 	client := &APIClient{
 		OrgID:      testOrgID,
 		APIBaseURL: "http://localhost",
 		apiSecret:  testAPISecret,
 	}
 
-	_, err := client.GetConfigWithID("invalid-uuid")
+	_, err := client.UpdateMonitor(context.Background(), "invalid-uuid", &Monitor{})
 	if err == nil {
 		t.Error("expected error for invalid UUID, got nil")
 	}
 }
 
-func TestGetAllConfigs_Mock(t *testing.T) {
-	expectedConfigs := []*Config{
-		{ID: "550e8400-e29b-41d4-a716-446655440001", Content: "config1"},
-		{ID: "550e8400-e29b-41d4-a716-446655440002", Content: "config2"},
+func TestDeleteMonitor(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected DELETE method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/monitors/" + testMonitorID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	if err := client.DeleteMonitor(context.Background(), testMonitorID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteMonitor_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "monitor not found"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	err := client.DeleteMonitor(context.Background(), testMonitorID)
+
+	if err == nil {
+		t.Fatal("expected error for 404 response, got nil")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got: %v", err)
 	}
+}
+
+// =============================================================================
+// Notification Channel API Unit Tests (Mock Server)
+// =============================================================================
 
+func TestGetNotificationChannel(t *testing.T) {
 	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			t.Errorf("expected GET method, got %s", r.Method)
 		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/notification_channels/" + testChannelID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(expectedConfigs); err != nil {
-			t.Errorf("failed to encode response: %v", err)
-		}
+		_, _ = w.Write([]byte(`{"channel_id": "` + testChannelID + `", "name": "On-call", "type": "pagerduty", "pagerduty": {"routing_key": "****"}}`))
 	})
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.GetAllConfigs()
+	result, err := client.GetNotificationChannel(context.Background(), testChannelID)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(result) != 2 {
-		t.Errorf("expected 2 configs, got %d", len(result))
+	if result.Type != PagerDutyNotificationChannelType {
+		t.Errorf("expected type pagerduty, got %s", result.Type)
+	}
+	if result.PagerDuty == nil {
+		t.Error("expected pagerduty settings, got nil")
 	}
 }
 
-func TestCreateConfig_Mock(t *testing.T) {
-	inputConfig := Config{
-		Content:     "new: config",
-		Environment: KubernetesEnvironmentType,
+func TestGetNotificationChannel_InvalidID(t *testing.T) {
+	client := &APIClient{
+		OrgID:      testOrgID,
+		APIBaseURL: "http://localhost",
+		apiSecret:  testAPISecret,
 	}
 
-	expectedResponse := CreateConfigResponse{
-		ID:      testConfigID,
-		Content: "new: config",
-		Tag:     "v1.0.0",
+	_, err := client.GetNotificationChannel(context.Background(), "invalid-uuid")
+	if err == nil {
+		t.Fatal("expected error for invalid UUID, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to validate the notification channel ID") {
+		t.Errorf("expected UUID validation error, got: %v", err)
+	}
+}
+
+func TestGetAllNotificationChannels(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/v1/orgs/" + testOrgID + "/notification_channels"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"channel_id": "` + testChannelID + `", "name": "Email", "type": "email", "email": {"addresses": ["oncall@example.com"]}}]`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.GetAllNotificationChannels(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 notification channel, got %d", len(result))
+	}
+	if result[0].Email == nil || result[0].Email.Addresses[0] != "oncall@example.com" {
+		t.Errorf("unexpected email settings: %+v", result[0].Email)
+	}
+}
+
+func TestCreateNotificationChannel(t *testing.T) {
+	inputChannel := &NotificationChannel{
+		Name:  "Alerts",
+		Type:  SlackNotificationChannelType,
+		Slack: &SlackChannelSettings{WebhookURL: "https://hooks.slack.com/services/T/B/X", Channel: "#alerts"},
 	}
 
 	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
@@ -446,45 +771,937 @@ func TestCreateConfig_Mock(t *testing.T) {
 			t.Errorf("expected POST method, got %s", r.Method)
 		}
 
+		// Verify request body
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		slack, _ := body["slack"].(map[string]interface{})
+		if body["type"] != "slack" || slack["webhook_url"] != inputChannel.Slack.WebhookURL {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		for _, key := range []string{"pagerduty", "teams", "webhook", "email"} {
+			if _, ok := body[key]; ok {
+				t.Errorf("expected no %s settings in the request body", key)
+			}
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
-		if err := json.NewEncoder(w).Encode(expectedResponse); err != nil {
-			t.Errorf("failed to encode response: %v", err)
+		_, _ = w.Write([]byte(`{"channel_id": "` + testChannelID + `", "name": "Alerts", "type": "slack", "slack": {"webhook_url": "https://hooks.slack.com/***", "channel": "#alerts"}}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.CreateNotificationChannel(context.Background(), inputChannel)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ChannelID != testChannelID {
+		t.Errorf("expected channel ID %s, got %s", testChannelID, result.ChannelID)
+	}
+}
+
+func TestUpdateNotificationChannel(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/notification_channels/" + testChannelID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
 		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"channel_id": "` + testChannelID + `", "name": "Renamed", "type": "teams"}`))
 	})
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	result, err := client.CreateConfig(inputConfig)
+	result, err := client.UpdateNotificationChannel(context.Background(), testChannelID, &NotificationChannel{Name: "Renamed"})
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if result.ID != testConfigID {
-		t.Errorf("expected config ID %s, got %s", testConfigID, result.ID)
+	if result.Name != "Renamed" {
+		t.Errorf("expected name Renamed, got %s", result.Name)
 	}
 }
 
-func TestDeleteConfigWithID_Mock(t *testing.T) {
+func TestDeleteNotificationChannel(t *testing.T) {
 	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
 			t.Errorf("expected DELETE method, got %s", r.Method)
 		}
-
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{}`))
-		if err != nil {
-			t.Errorf("failed to write response: %v", err)
+		expectedPath := "/v1/orgs/" + testOrgID + "/notification_channels/" + testChannelID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
 		}
+
+		w.WriteHeader(http.StatusNoContent)
 	})
 	defer server.Close()
 
 	client := newTestClient(server.URL)
-	if err := client.DeleteConfigWithID(testConfigID); err != nil {
+	if err := client.DeleteNotificationChannel(context.Background(), testChannelID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
+func TestDeleteNotificationChannel_InvalidID(t *testing.T) {
+	client := &APIClient{
+		OrgID:      testOrgID,
+		APIBaseURL: "http://localhost",
+		apiSecret:  testAPISecret,
+	}
+
+	if err := client.DeleteNotificationChannel(context.Background(), "invalid-uuid"); err == nil {
+		t.Error("expected error for invalid UUID, got nil")
+	}
+}
+
+// =============================================================================
+// API Token API Unit Tests (Mock Server)
+// =============================================================================
+
+func TestCreateAPIToken(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/api_tokens"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token_id": "` + testTokenID + `", "name": "ci", "scopes": ["pipelines:read"], "token": "ed-secret"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.CreateAPIToken(context.Background(), &APIToken{Name: "ci", Scopes: []string{"pipelines:read"}})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TokenID != testTokenID || result.Token != "ed-secret" {
+		t.Errorf("unexpected API token: %+v", result)
+	}
+}
+
+func TestGetAPIToken(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/v1/orgs/" + testOrgID + "/api_tokens/" + testTokenID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token_id": "` + testTokenID + `", "name": "ci", "scopes": ["pipelines:read"], "expires_at": "2024-07-01T00:00:00Z"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.GetAPIToken(context.Background(), testTokenID)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExpiresAt != "2024-07-01T00:00:00Z" {
+		t.Errorf("unexpected expiry %s", result.ExpiresAt)
+	}
+}
+
+func TestGetAPIToken_InvalidID(t *testing.T) {
+	client := &APIClient{
+		OrgID:      testOrgID,
+		APIBaseURL: "http://localhost",
+		apiSecret:  testAPISecret,
+	}
+
+	_, err := client.GetAPIToken(context.Background(), "invalid-uuid")
+	if err == nil {
+		t.Fatal("expected error for invalid UUID, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to validate the API token ID") {
+		t.Errorf("expected UUID validation error, got: %v", err)
+	}
+}
+
+func TestDeleteAPIToken(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected DELETE method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/api_tokens/" + testTokenID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	if err := client.DeleteAPIToken(context.Background(), testTokenID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// =============================================================================
+// User, Team and Role API Unit Tests (Mock Server)
+// =============================================================================
+
+func TestInviteUser(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/users"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+		var user User
+		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if user.Email != "jane.doe@example.com" || user.Role != "admin" {
+			t.Errorf("unexpected request body: %+v", user)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"user_id": "` + testUserID + `", "email": "jane.doe@example.com", "role": "admin", "status": "invited"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.InviteUser(context.Background(), &User{Email: "jane.doe@example.com", Role: "admin"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.UserID != testUserID || result.Status != InvitedUserStatus {
+		t.Errorf("unexpected user: %+v", result)
+	}
+}
+
+func TestRevokeUser(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/users/" + testUserID + "/revoke"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	if err := client.RevokeUser(context.Background(), testUserID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetUser_InvalidID(t *testing.T) {
+	client := &APIClient{
+		OrgID:      testOrgID,
+		APIBaseURL: "http://localhost",
+		apiSecret:  testAPISecret,
+	}
+
+	_, err := client.GetUser(context.Background(), "jane.doe@example.com")
+	if err == nil {
+		t.Fatal("expected error for invalid UUID, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to validate the user ID") {
+		t.Errorf("expected UUID validation error, got: %v", err)
+	}
+}
+
+func TestUpdateTeam(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/teams/" + testTeamID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"team_id": "` + testTeamID + `", "name": "sre", "members": ["` + testUserID + `"]}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.UpdateTeam(context.Background(), testTeamID, &Team{Name: "sre", Members: []string{testUserID}})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Members) != 1 || result.Members[0] != testUserID {
+		t.Errorf("unexpected members: %v", result.Members)
+	}
+}
+
+func TestCreateRole(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/roles"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"role_id": "` + testRoleID + `", "name": "pipeline-editor", "permissions": ["pipelines:write"]}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.CreateRole(context.Background(), &Role{Name: "pipeline-editor", Permissions: []string{"pipelines:write"}})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RoleID != testRoleID {
+		t.Errorf("expected role ID %s, got %s", testRoleID, result.RoleID)
+	}
+}
+
+func TestDeleteRole_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "role not found"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	err := client.DeleteRole(context.Background(), testRoleID)
+
+	if err == nil {
+		t.Fatal("expected error for 404 response, got nil")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got: %v", err)
+	}
+}
+
+// =============================================================================
+// Config API Unit Tests (Mock Server)
+// =============================================================================
+
+func TestGetConfigWithID_Mock(t *testing.T) {
+	expectedConfig := GetConfigResponse{
+		ID:          testConfigID,
+		Content:     "test: config",
+		Description: "Test config",
+		Tag:         "v1.0.0",
+	}
+
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		expectedPath := "/v1/orgs/" + testOrgID + "/confs/" + testConfigID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(expectedConfig); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.GetConfigWithID(context.Background(), testConfigID)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != expectedConfig.ID {
+		t.Errorf("expected config ID %s, got %s", expectedConfig.ID, result.ID)
+	}
+}
+
+func TestGetConfigWithID_InvalidID(t *testing.T) {
+	client := &APIClient{
+		OrgID:      testOrgID,
+		APIBaseURL: "http://localhost",
+		apiSecret:  testAPISecret,
+	}
+
+	_, err := client.GetConfigWithID(context.Background(), "invalid-uuid")
+	if err == nil {
+		t.Error("expected error for invalid UUID, got nil")
+	}
+}
+
+func TestGetAllConfigs_Mock(t *testing.T) {
+	expectedConfigs := []*Config{
+		{ID: "550e8400-e29b-41d4-a716-446655440001", Content: "config1"},
+		{ID: "550e8400-e29b-41d4-a716-446655440002", Content: "config2"},
+	}
+
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(expectedConfigs); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.GetAllConfigs(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected 2 configs, got %d", len(result))
+	}
+}
+
+func TestCreateConfig_Mock(t *testing.T) {
+	inputConfig := Config{
+		Content:     "new: config",
+		Environment: KubernetesEnvironmentType,
+	}
+
+	expectedResponse := CreateConfigResponse{
+		ID:      testConfigID,
+		Content: "new: config",
+		Tag:     "v1.0.0",
+	}
+
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(expectedResponse); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	result, err := client.CreateConfig(context.Background(), inputConfig)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != testConfigID {
+		t.Errorf("expected config ID %s, got %s", testConfigID, result.ID)
+	}
+}
+
+func TestDeleteConfigWithID_Mock(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected DELETE method, got %s", r.Method)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{}`))
+		if err != nil {
+			t.Errorf("failed to write response: %v", err)
+		}
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	if err := client.DeleteConfigWithID(context.Background(), testConfigID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// =============================================================================
+// API Error Unit Tests (Mock Server)
+// =============================================================================
+
+func TestAPIError_Fields(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error": "config was modified"}`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	_, err := client.UpdateConfigWithID(context.Background(), testConfigID, Config{Content: "a: b"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got %T: %v", err, err)
+	}
+	if apiErr.Method != http.MethodPut {
+		t.Errorf("expected method PUT, got %s", apiErr.Method)
+	}
+	expectedPath := "/v1/orgs/" + testOrgID + "/confs/" + testConfigID
+	if apiErr.Path != expectedPath {
+		t.Errorf("expected path %s, got %s", expectedPath, apiErr.Path)
+	}
+	if apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Message != "config was modified" {
+		t.Errorf("expected server message, got %q", apiErr.Message)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("expected request ID req-123, got %q", apiErr.RequestID)
+	}
+	if !IsConflict(err) || IsNotFound(err) || IsRateLimited(err) {
+		t.Errorf("unexpected classification for %v", err)
+	}
+}
+
+func TestAPIError_StatusCodeReturned(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`slow down`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	_, status, err := client.doRequest(context.Background(), "confs", "", http.MethodGet, true, true, nil)
+	if status != http.StatusTooManyRequests {
+		t.Errorf("expected status 429, got %d", status)
+	}
+	if !IsRateLimited(err) {
+		t.Errorf("expected a rate limited error, got: %v", err)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Message != "slow down" {
+		t.Errorf("expected raw body as message, got %q", apiErr.Message)
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	wrapped := fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusNotFound})
+	if !IsNotFound(wrapped) {
+		t.Error("expected IsNotFound to see through wrapped errors")
+	}
+	if IsNotFound(errors.New("404 not found")) {
+		t.Error("expected IsNotFound to ignore non API errors")
+	}
+	if IsNotFound(nil) || IsConflict(nil) || IsRateLimited(nil) {
+		t.Error("expected nil error not to match")
+	}
+}
+
+// =============================================================================
+// Retry Unit Tests (Mock Server)
+// =============================================================================
+
+// newRetryTestClient returns a test client that retries without noticeable waits
+func newRetryTestClient(serverURL string, maxRetries int) *APIClient {
+	client := newTestClient(serverURL)
+	client.MaxRetries = maxRetries
+	client.RetryMaxWait = 5 * time.Millisecond
+	client.retryWaitMin = time.Millisecond
+	return client
+}
+
+// newSequenceServer returns a mock server that answers with the given status codes in order,
+// repeating the last one, and counts the requests it receives
+func newSequenceServer(t *testing.T, statuses []int, body string, calls *int32) *httptest.Server {
+	return newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(calls, 1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statuses[n])
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("failed to write response: %v", err)
+		}
+	})
+}
+
+func TestDoRequest_RetriesIdempotentOn5xx(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, `{"id": "`+testConfigID+`"}`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	result, err := client.GetConfigWithID(context.Background(), testConfigID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != testConfigID {
+		t.Errorf("expected config ID %s, got %s", testConfigID, result.ID)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestDoRequest_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusBadGateway}, `bad gateway`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 2)
+	_, err := client.GetConfigWithID(context.Background(), testConfigID)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "502") {
+		t.Errorf("expected error to mention status 502, got: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls (1 + 2 retries), got %d", calls)
+	}
+}
+
+func TestDoRequest_NoRetriesByDefault(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusBadGateway, http.StatusOK}, `{}`, &calls)
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	if _, err := client.GetConfigWithID(context.Background(), testConfigID); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestDoRequest_DoesNotRetryNonIdempotentOn5xx(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusBadGateway, http.StatusCreated}, `{"id": "`+testConfigID+`"}`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	if _, err := client.CreateConfig(context.Background(), Config{Content: "new: config"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestDoRequest_RetriesIdempotentEndpointsOn5xx(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusOK}, `{"id": "`+testConfigID+`"}`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	if _, err := client.DeployConfig(context.Background(), testConfigID, 1000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestDoRequest_DoesNotRetrySaveConfigOn5xx(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusBadGateway, http.StatusOK}, `{"id": "`+testConfigID+`"}`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	content := "new: config"
+	if _, err := client.SaveConfig(context.Background(), testConfigID, SaveRequest{Content: &content}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestDoRequest_RetriesRateLimitedForAllMethods(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusTooManyRequests, http.StatusCreated}, `{"id": "`+testConfigID+`"}`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	result, err := client.CreateConfig(context.Background(), Config{Content: "new: config"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != testConfigID {
+		t.Errorf("expected config ID %s, got %s", testConfigID, result.ID)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestDoRequest_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusBadRequest, http.StatusOK}, `{}`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	if _, err := client.GetConfigWithID(context.Background(), testConfigID); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestDoRequest_ResendsBodyOnRetry(t *testing.T) {
+	var calls int32
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var received Dashboard
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if received.DashboardName != "Retried" {
+			t.Errorf("expected name Retried, got %q", received.DashboardName)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"dashboard_id": "` + testDashboardID + `"}`))
+	})
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	if _, err := client.UpdateDashboard(context.Background(), testDashboardID, &Dashboard{DashboardName: "Retried"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestDoRequest_RetriesTransportErrors(t *testing.T) {
+	var calls int32
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Drop the connection without answering
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				t.Error("response writer doesn't support hijacking")
+				return
+			}
+			conn, _, err := hj.Hijack()
+			if err != nil {
+				t.Errorf("failed to hijack connection: %v", err)
+				return
+			}
+			_ = conn.Close()
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	if _, err := client.GetAllDashboards(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestDoRequest_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	var first time.Time
+	var waited time.Duration
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		waited = time.Since(first)
+		_, _ = w.Write([]byte(`[]`))
+	})
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 1)
+	client.RetryMaxWait = 5 * time.Second
+	if _, err := client.GetAllDashboards(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if waited < time.Second {
+		t.Errorf("expected to wait at least 1s as requested by Retry-After, waited %s", waited)
+	}
+}
+
+func TestDoRequest_CancelsInFlightRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	client := newRetryTestClient(server.URL, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetConfigWithID(ctx, testConfigID)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline exceeded error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the request to be cancelled quickly, took %s", elapsed)
+	}
+}
+
+func TestDoRequest_WrapsCancellation(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	client := newRetryTestClient(server.URL, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := client.GetConfigWithID(ctx, testConfigID)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a context canceled error, got: %v", err)
+	}
+}
+
+func TestDoRequest_StopsRetryingWhenContextIsCancelled(t *testing.T) {
+	var calls int32
+	server := newSequenceServer(t, []int{http.StatusServiceUnavailable}, `unavailable`, &calls)
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 5)
+	client.retryWaitMin = time.Hour
+	client.RetryMaxWait = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := client.GetAllConfigs(ctx)
+	if apiErrorStatus(err) != http.StatusServiceUnavailable {
+		t.Errorf("expected the last API error to be returned, got: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the retry wait to be interrupted, took %s", elapsed)
+	}
+}
+
+func TestDoRequest_DoesNotWaitPastDeadline(t *testing.T) {
+	var calls int32
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, 3)
+	client.RetryMaxWait = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetAllDashboards(ctx)
+	if !IsRateLimited(err) {
+		t.Errorf("expected a rate limited error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to give up without waiting, took %s", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	client := &APIClient{RetryMaxWait: 10 * time.Second, retryWaitMin: time.Second}
+	for attempt := 0; attempt < 6; attempt++ {
+		backoff := time.Second << uint(attempt)
+		if backoff > client.RetryMaxWait {
+			backoff = client.RetryMaxWait
+		}
+		wait := client.retryWait(attempt, nil)
+		if wait < backoff/2 || wait > backoff {
+			t.Errorf("attempt %d: expected wait between %s and %s, got %s", attempt, backoff/2, backoff, wait)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "3")
+	if wait := client.retryWait(0, resp); wait != 3*time.Second {
+		t.Errorf("expected Retry-After wait of 3s, got %s", wait)
+	}
+	resp.Header.Set("Retry-After", "120")
+	if wait := client.retryWait(0, resp); wait != client.RetryMaxWait {
+		t.Errorf("expected Retry-After wait to be capped at %s, got %s", client.RetryMaxWait, wait)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "5", want: 5 * time.Second, wantOK: true},
+		{value: "0", want: 0, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "soon", wantOK: false},
+		{value: now.Add(7 * time.Second).Format(http.TimeFormat), want: 7 * time.Second, wantOK: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
 // =============================================================================
 // Integration Tests (Require Real API - Skip if no credentials)
 // =============================================================================
@@ -500,7 +1717,7 @@ func TestGetConfigWithID_Integration(t *testing.T) {
 		APIBaseURL: *apiEndpoint,
 	}
 
-	confObject, err := cli.GetConfigWithID(*confID)
+	confObject, err := cli.GetConfigWithID(context.Background(), *confID)
 	if err != nil {
 		t.Error(err)
 	}
@@ -529,7 +1746,7 @@ func TestUpdateConfigWithID_Integration(t *testing.T) {
 		Content: string(confDataRaw[:]),
 	}
 
-	confObject, err := cli.UpdateConfigWithID(*confID, confData)
+	confObject, err := cli.UpdateConfigWithID(context.Background(), *confID, confData)
 	if err != nil {
 		t.Error(err)
 		return
@@ -559,7 +1776,7 @@ func TestCreateConfig_Integration(t *testing.T) {
 		Content: string(confDataRaw[:]),
 	}
 
-	confObject, err := cli.CreateConfig(confData)
+	confObject, err := cli.CreateConfig(context.Background(), confData)
 	if err != nil {
 		t.Error(err)
 		return
@@ -0,0 +1,92 @@
+package edgedelta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned by APIClient methods when the API responds with a non OK http status
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Message is the error message returned by the API, or the raw response body if it has none
+	Message   string
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("got non OK http status from: %s %s, status: %d, response: %q", e.Method, e.Path, e.StatusCode, e.Message)
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(", request ID: %s", e.RequestID)
+	}
+	return msg
+}
+
+// newAPIError builds an APIError from a non OK http response and its already read body
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		Method:     req.Method,
+		Path:       req.URL.RequestURI(),
+		StatusCode: resp.StatusCode,
+		Message:    apiErrorMessage(body),
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+}
+
+// apiErrorMessage extracts the error message from an API error response body.
+// The API reports errors as {"error": "..."} or {"message": "..."}; anything else is returned as is.
+func apiErrorMessage(body []byte) string {
+	var resp struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil {
+		if resp.Error != "" {
+			return resp.Error
+		}
+		if resp.Message != "" {
+			return resp.Message
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// apiErrorStatus returns the http status code of err if it wraps an APIError, and 0 otherwise
+func apiErrorStatus(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an API error for a resource that does not exist
+func IsNotFound(err error) bool {
+	return apiErrorStatus(err) == http.StatusNotFound
+}
+
+// IsConflict reports whether err is an API error caused by a conflicting change
+func IsConflict(err error) bool {
+	return apiErrorStatus(err) == http.StatusConflict
+}
+
+// IsRateLimited reports whether err is an API error caused by rate limiting
+func IsRateLimited(err error) bool {
+	return apiErrorStatus(err) == http.StatusTooManyRequests
+}
+
+// apiErrorDetail returns the diagnostic detail for an error returned by an APIClient method,
+// with a hint for the errors that the user can act on
+func apiErrorDetail(err error) string {
+	switch {
+	case IsConflict(err):
+		return fmt.Sprintf("%s\n\nThe resource was changed outside of Terraform in the meantime. Run `terraform refresh` and try again.", err)
+	case IsRateLimited(err):
+		return fmt.Sprintf("%s\n\nThe API is rate limiting requests. Consider increasing max_retries or retry_max_wait in the provider configuration.", err)
+	}
+	return err.Error()
+}
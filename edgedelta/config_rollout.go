@@ -0,0 +1,199 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	defaultRolloutTimeout      = 10 * time.Minute
+	defaultRolloutPollInterval = 10 * time.Second
+	// defaultDeployTimeout covers the save, the deploy and the rollout wait of an apply
+	defaultDeployTimeout = 20 * time.Minute
+)
+
+// rolloutOptions configures how long to wait for agents to pick up a deployed config
+type rolloutOptions struct {
+	minHealthyPercent int
+	timeout           time.Duration
+	pollInterval      time.Duration
+}
+
+func rolloutSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Wait after each deployment until enough agents run the deployed version. The apply fails if they don't within the timeout.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"min_healthy_percent": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      100,
+					ValidateFunc: validation.IntBetween(0, 100),
+					Description:  "Percentage of the agents that must be healthy and running the deployed version",
+				},
+				"timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultRolloutTimeout.String(),
+					ValidateFunc: validateDuration,
+					Description:  "How long to wait for the rollout, as a duration (e.g. 10m)",
+				},
+				"poll_interval": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultRolloutPollInterval.String(),
+					ValidateFunc: validateDuration,
+					Description:  "How often to poll the agent status, as a duration (e.g. 10s)",
+				},
+			},
+		},
+	}
+}
+
+// rolloutTimeouts returns the Timeouts of the resources that deploy configs. The rollout wait is
+// part of the create and update operations, so it never outlasts their timeout.
+func rolloutTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultDeployTimeout),
+		Update: schema.DefaultTimeout(defaultDeployTimeout),
+	}
+}
+
+// within caps the rollout timeout to the timeout of the operation that deploys the config
+func (o *rolloutOptions) within(operationTimeout time.Duration) *rolloutOptions {
+	if o == nil || o.timeout <= operationTimeout {
+		return o
+	}
+	bounded := *o
+	bounded.timeout = operationTimeout
+	return &bounded
+}
+
+// parseRolloutOptions returns nil if no wait_for_rollout block is set
+func parseRolloutOptions(raw interface{}) (*rolloutOptions, error) {
+	blocks, ok := raw.([]interface{})
+	if !ok || len(blocks) == 0 || blocks[0] == nil {
+		return nil, nil
+	}
+	block := blocks[0].(map[string]interface{})
+	timeout, err := time.ParseDuration(block["timeout"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid wait_for_rollout.timeout: %v", err)
+	}
+	pollInterval, err := time.ParseDuration(block["poll_interval"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid wait_for_rollout.poll_interval: %v", err)
+	}
+	return &rolloutOptions{
+		minHealthyPercent: block["min_healthy_percent"].(int),
+		timeout:           timeout,
+		pollInterval:      pollInterval,
+	}, nil
+}
+
+// rolledOutPercent returns the percentage of agents that are healthy and run version.
+// A fleet without agents counts as fully rolled out.
+func rolledOutPercent(status *PipelineStatus, version int64) float64 {
+	if len(status.Agents) == 0 {
+		return 100
+	}
+	done := 0
+	for _, a := range status.Agents {
+		if a.Healthy && a.ConfigVersion == version {
+			done++
+		}
+	}
+	return float64(done) * 100 / float64(len(status.Agents))
+}
+
+// waitForRollout polls the pipeline status until at least opts.minHealthyPercent of the agents
+// are healthy and run version. On timeout the error lists the agents that are not there yet,
+// as of the last status that could be read.
+func waitForRollout(ctx context.Context, client *APIClient, confID string, version int64, opts *rolloutOptions) diag.Diagnostics {
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	start := time.Now()
+	var status *PipelineStatus
+	var lastErr error
+	for {
+		// A failed poll must not discard the last status that was read
+		polled, err := client.GetPipelineStatus(ctx, confID)
+		if err == nil {
+			status, lastErr = polled, nil
+			if rolledOutPercent(status, version) >= float64(opts.minHealthyPercent) {
+				return nil
+			}
+		} else {
+			lastErr = err
+		}
+		if sleepWithContext(ctx, opts.pollInterval) != nil {
+			break
+		}
+	}
+	waited := time.Since(start).Round(time.Second)
+
+	if status == nil {
+		detail := fmt.Sprintf("Config was deployed (version %d) but its rollout status could not be read within %s", version, waited)
+		if lastErr != nil {
+			detail += fmt.Sprintf(": %s", apiErrorDetail(lastErr))
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Could not wait for the config rollout",
+			Detail:   detail,
+		}}
+	}
+	detail := fmt.Sprintf("Config was deployed (version %d) but only %.0f%% of the agents were healthy and running it after %s (%d%% required).\n\n%s",
+		version, rolledOutPercent(status, version), waited, opts.minHealthyPercent, formatAgentBreakdown(status, version))
+	if lastErr != nil {
+		detail += fmt.Sprintf("\n\nThe last status poll failed: %s", apiErrorDetail(lastErr))
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Config rollout did not complete",
+		Detail:   detail,
+	}}
+}
+
+// formatAgentBreakdown lists the agents that are unhealthy or don't run version yet
+func formatAgentBreakdown(status *PipelineStatus, version int64) string {
+	agents := append([]AgentStatus(nil), status.Agents...)
+	sort.Slice(agents, func(i, j int) bool { return agents[i].AgentID < agents[j].AgentID })
+
+	lines := []string{"Agents that are not rolled out:"}
+	for _, a := range agents {
+		if a.Healthy && a.ConfigVersion == version {
+			continue
+		}
+		name := a.AgentID
+		if a.Hostname != "" {
+			name = fmt.Sprintf("%s (%s)", a.Hostname, a.AgentID)
+		}
+		var problems []string
+		if a.ConfigVersion != version {
+			problems = append(problems, fmt.Sprintf("running version %d", a.ConfigVersion))
+		}
+		if !a.Healthy {
+			problems = append(problems, "unhealthy")
+		}
+		if a.Error != "" {
+			problems = append(problems, fmt.Sprintf("error: %s", a.Error))
+		}
+		if a.LastSeen != "" {
+			problems = append(problems, fmt.Sprintf("last seen %s", a.LastSeen))
+		}
+		lines = append(lines, fmt.Sprintf("  - %s: %s", name, strings.Join(problems, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
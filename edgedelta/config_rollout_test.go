@@ -0,0 +1,175 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForRollout(t *testing.T) {
+	const version = int64(1700000000)
+	var calls int32
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/v1/orgs/" + testOrgID + "/pipelines/" + testConfigID + "/status"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+		status := PipelineStatus{
+			ConfigID: testConfigID,
+			Agents: []AgentStatus{
+				{AgentID: "a1", ConfigVersion: version, Healthy: true},
+				{AgentID: "a2", ConfigVersion: version - 1, Healthy: true},
+			},
+		}
+		// The second agent picks up the new version on the third poll
+		if atomic.AddInt32(&calls, 1) >= 3 {
+			status.Agents[1].ConfigVersion = version
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	})
+	defer server.Close()
+
+	opts := &rolloutOptions{minHealthyPercent: 100, timeout: 5 * time.Second, pollInterval: time.Millisecond}
+	diags := waitForRollout(context.Background(), newTestClient(server.URL), testConfigID, version, opts)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 polls, got %d", calls)
+	}
+}
+
+func TestWaitForRollout_MinHealthyPercent(t *testing.T) {
+	const version = int64(1700000000)
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(PipelineStatus{Agents: []AgentStatus{
+			{AgentID: "a1", ConfigVersion: version, Healthy: true},
+			{AgentID: "a2", ConfigVersion: version, Healthy: true},
+			{AgentID: "a3", ConfigVersion: version, Healthy: true},
+			{AgentID: "a4", ConfigVersion: version, Healthy: false},
+		}})
+	})
+	defer server.Close()
+
+	opts := &rolloutOptions{minHealthyPercent: 75, timeout: 5 * time.Second, pollInterval: time.Millisecond}
+	if diags := waitForRollout(context.Background(), newTestClient(server.URL), testConfigID, version, opts); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+}
+
+func TestWaitForRollout_Stalled(t *testing.T) {
+	const version = int64(1700000000)
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(PipelineStatus{Agents: []AgentStatus{
+			{AgentID: "a1", Hostname: "node-1", ConfigVersion: version, Healthy: true},
+			{AgentID: "a2", Hostname: "node-2", ConfigVersion: version - 1, Healthy: true, LastSeen: "2024-01-01T00:00:00Z"},
+			{AgentID: "a3", ConfigVersion: version, Healthy: false, Error: "failed to start node ed_output"},
+		}})
+	})
+	defer server.Close()
+
+	opts := &rolloutOptions{minHealthyPercent: 100, timeout: 50 * time.Millisecond, pollInterval: 10 * time.Millisecond}
+	diags := waitForRollout(context.Background(), newTestClient(server.URL), testConfigID, version, opts)
+	if !diags.HasError() {
+		t.Fatal("expected an error for a stalled rollout")
+	}
+	detail := diags[0].Detail
+	for _, want := range []string{
+		"only 33% of the agents",
+		"node-2 (a2): running version 1699999999, last seen 2024-01-01T00:00:00Z",
+		"a3: unhealthy, error: failed to start node ed_output",
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("expected detail to contain %q, got:\n%s", want, detail)
+		}
+	}
+	if strings.Contains(detail, "node-1") {
+		t.Errorf("expected rolled out agents to be left out of the breakdown, got:\n%s", detail)
+	}
+}
+
+func TestWaitForRollout_StatusUnavailable(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	opts := &rolloutOptions{minHealthyPercent: 100, timeout: 30 * time.Millisecond, pollInterval: 10 * time.Millisecond}
+	diags := waitForRollout(context.Background(), newTestClient(server.URL), testConfigID, 1, opts)
+	if !diags.HasError() || diags[0].Summary != "Could not wait for the config rollout" {
+		t.Fatalf("expected a status error, got %v", diags)
+	}
+}
+
+func TestWaitForRollout_KeepsLastStatus(t *testing.T) {
+	const version = int64(1700000000)
+	var calls int32
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		// Only the first poll succeeds
+		if atomic.AddInt32(&calls, 1) > 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(PipelineStatus{Agents: []AgentStatus{
+			{AgentID: "a1", ConfigVersion: version, Healthy: true},
+			{AgentID: "a2", ConfigVersion: version - 1, Healthy: true},
+		}})
+	})
+	defer server.Close()
+
+	opts := &rolloutOptions{minHealthyPercent: 100, timeout: 50 * time.Millisecond, pollInterval: 10 * time.Millisecond}
+	diags := waitForRollout(context.Background(), newTestClient(server.URL), testConfigID, version, opts)
+	if !diags.HasError() || diags[0].Summary != "Config rollout did not complete" {
+		t.Fatalf("expected a rollout error with the last status, got %v", diags)
+	}
+	for _, want := range []string{"only 50% of the agents", "a2: running version 1699999999", "The last status poll failed"} {
+		if !strings.Contains(diags[0].Detail, want) {
+			t.Errorf("expected detail to contain %q, got:\n%s", want, diags[0].Detail)
+		}
+	}
+}
+
+func TestRolloutOptionsWithin(t *testing.T) {
+	var none *rolloutOptions
+	if none.within(time.Minute) != nil {
+		t.Error("expected no options without a wait_for_rollout block")
+	}
+	opts := &rolloutOptions{timeout: 30 * time.Minute}
+	if got := opts.within(20 * time.Minute).timeout; got != 20*time.Minute {
+		t.Errorf("expected the rollout timeout to be capped to the operation timeout, got %s", got)
+	}
+	if got := opts.within(time.Hour).timeout; got != 30*time.Minute {
+		t.Errorf("expected a shorter rollout timeout to be kept, got %s", got)
+	}
+	if opts.timeout != 30*time.Minute {
+		t.Error("expected the options to be left unchanged")
+	}
+}
+
+func TestRolledOutPercent_NoAgents(t *testing.T) {
+	if got := rolledOutPercent(&PipelineStatus{}, 1); got != 100 {
+		t.Errorf("expected a fleet without agents to be fully rolled out, got %.0f%%", got)
+	}
+}
+
+func TestParseRolloutOptions(t *testing.T) {
+	opts, err := parseRolloutOptions([]interface{}{})
+	if err != nil || opts != nil {
+		t.Errorf("expected no options without a block, got %+v, %v", opts, err)
+	}
+	opts, err = parseRolloutOptions([]interface{}{map[string]interface{}{
+		"min_healthy_percent": 90,
+		"timeout":             "5m",
+		"poll_interval":       "15s",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.minHealthyPercent != 90 || opts.timeout != 5*time.Minute || opts.pollInterval != 15*time.Second {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
@@ -0,0 +1,185 @@
+package edgedelta
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables read by the provider when the corresponding setting is not in the provider block
+const (
+	OrgIDEnvVar           = "EDGEDELTA_ORG_ID"
+	APISecretEnvVar       = "EDGEDELTA_API_SECRET"
+	APIEndpointEnvVar     = "EDGEDELTA_API_ENDPOINT"
+	ProfileEnvVar         = "EDGEDELTA_PROFILE"
+	CredentialsFileEnvVar = "EDGEDELTA_CREDENTIALS_FILE"
+)
+
+const (
+	defaultAPIEndpoint = "https://api.edgedelta.com"
+	defaultProfile     = "default"
+)
+
+// credentialsProfile holds the settings of a named profile in the credentials file
+type credentialsProfile struct {
+	OrgID       string
+	APISecret   string
+	APIEndpoint string
+}
+
+// defaultCredentialsFile returns ~/.edgedelta/credentials, or "" if the home directory is unknown
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".edgedelta", "credentials")
+}
+
+// loadCredentialsProfile reads a named profile from an INI style credentials file:
+//
+//	[default]
+//	org_id       = 00000000-0000-0000-0000-000000000000
+//	api_secret   = ...
+//	api_endpoint = https://api.edgedelta.com
+//
+// It returns nil without an error if the file or the profile doesn't exist.
+func loadCredentialsProfile(path, name string) (*credentialsProfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open the credentials file '%s': %v", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var profile *credentialsProfile
+	inProfile := false
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("invalid section header in the credentials file '%s' at line %d", path, lineNum)
+			}
+			inProfile = strings.TrimSpace(line[1:len(line)-1]) == name
+			if inProfile && profile == nil {
+				profile = &credentialsProfile{}
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line in the credentials file '%s' at line %d, expected 'key = value'", path, lineNum)
+		}
+		if !inProfile {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "org_id":
+			profile.OrgID = value
+		case "api_secret":
+			profile.APISecret = value
+		case "api_endpoint":
+			profile.APIEndpoint = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the credentials file '%s': %v", path, err)
+	}
+	return profile, nil
+}
+
+// providerCredentials are the resolved connection settings of the provider
+type providerCredentials struct {
+	OrgID       string
+	APISecret   string
+	APIEndpoint string
+}
+
+// resolveCredentials fills the settings that are neither in the provider block nor in the
+// environment (both already applied to explicit) from the credentials file profile, and
+// falls back to the default API endpoint. If profile is empty, the default profile is used
+// when it exists; a profile that is set explicitly must exist.
+func resolveCredentials(explicit providerCredentials, profile, credentialsFile string) (*providerCredentials, error) {
+	creds := explicit
+	if creds.OrgID == "" || creds.APISecret == "" || creds.APIEndpoint == "" || profile != "" {
+		if credentialsFile == "" {
+			credentialsFile = defaultCredentialsFile()
+		} else if strings.HasPrefix(credentialsFile, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				credentialsFile = filepath.Join(home, credentialsFile[2:])
+			}
+		}
+		profileName := profile
+		if profileName == "" {
+			profileName = defaultProfile
+		}
+		var p *credentialsProfile
+		if credentialsFile != "" {
+			var err error
+			if p, err = loadCredentialsProfile(credentialsFile, profileName); err != nil {
+				return nil, err
+			}
+		}
+		if p == nil && profile != "" {
+			return nil, fmt.Errorf("profile '%s' not found in the credentials file '%s'", profile, credentialsFile)
+		}
+		if p != nil {
+			if creds.OrgID == "" {
+				creds.OrgID = p.OrgID
+			}
+			if creds.APISecret == "" {
+				creds.APISecret = p.APISecret
+			}
+			if creds.APIEndpoint == "" {
+				creds.APIEndpoint = p.APIEndpoint
+			}
+		}
+	}
+	if creds.APIEndpoint == "" {
+		creds.APIEndpoint = defaultAPIEndpoint
+	}
+	if creds.OrgID == "" {
+		return nil, fmt.Errorf("org_id is required: set it in the provider block, with the %s environment variable or in a credentials file profile", OrgIDEnvVar)
+	}
+	if creds.APISecret == "" {
+		return nil, fmt.Errorf("api_secret is required: set it in the provider block, with the %s environment variable or in a credentials file profile", APISecretEnvVar)
+	}
+	return &creds, nil
+}
+
+// NewAPIClientFromEnvironment returns a client configured the way the provider is without a
+// provider block: from the environment variables, then from the credentials file profile.
+// opts sets the retry and connection settings that the provider block would otherwise set.
+// It is meant for tools that talk to the API outside Terraform, such as cmd/tfgen.
+func NewAPIClientFromEnvironment(profile, credentialsFile string, opts APIClientOptions) (*APIClient, error) {
+	if profile == "" {
+		profile = os.Getenv(ProfileEnvVar)
+	}
+	if credentialsFile == "" {
+		credentialsFile = os.Getenv(CredentialsFileEnvVar)
+	}
+	creds, err := resolveCredentials(
+		providerCredentials{
+			OrgID:       os.Getenv(OrgIDEnvVar),
+			APISecret:   os.Getenv(APISecretEnvVar),
+			APIEndpoint: os.Getenv(APIEndpointEnvVar),
+		},
+		profile,
+		credentialsFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return NewAPIClient(creds.OrgID, creds.APIEndpoint, creds.APISecret, opts), nil
+}
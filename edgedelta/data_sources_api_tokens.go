@@ -0,0 +1,87 @@
+package edgedelta
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAPITokens() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAPITokensRead,
+		Description: "Lists the metadata of the EdgeDelta API tokens of the organization. Secret values are never returned.",
+		Schema: map[string]*schema.Schema{
+			// Computed
+			"tokens": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "API tokens, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":          {Type: schema.TypeString, Computed: true, Description: "ID of the API token."},
+						"name":        {Type: schema.TypeString, Computed: true, Description: "Name of the API token."},
+						"description": {Type: schema.TypeString, Computed: true, Description: "Description of the API token."},
+						"scopes": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Permission scopes granted to the API token.",
+						},
+						"expires_at": {Type: schema.TypeString, Computed: true, Description: "Expiry of the API token, empty if it never expires."},
+						"creator":    {Type: schema.TypeString, Computed: true, Description: "User ID who created the API token."},
+						"created":    {Type: schema.TypeString, Computed: true, Description: "UTC timestamp of API token creation."},
+						"last_used":  {Type: schema.TypeString, Computed: true, Description: "UTC timestamp of the last request made with the API token."},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAPITokensRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	meta := m.(*ProviderMetadata)
+
+	resp, err := meta.client.GetAllAPITokens(ctx)
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not get the API tokens from API",
+			Detail:   apiErrorDetail(err),
+		})
+	}
+
+	var sorted []*APIToken
+	for _, t := range resp {
+		if t != nil {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].TokenID < sorted[j].TokenID
+	})
+
+	// Only metadata is copied, even if the API were to include a secret
+	tokens := make([]map[string]interface{}, len(sorted))
+	for i, t := range sorted {
+		tokens[i] = map[string]interface{}{
+			"id":          t.TokenID,
+			"name":        t.Name,
+			"description": t.Description,
+			"scopes":      stringSliceToInterface(t.Scopes),
+			"expires_at":  t.ExpiresAt,
+			"creator":     t.Creator,
+			"created":     t.Created,
+			"last_used":   t.LastUsed,
+		}
+	}
+
+	d.SetId(meta.client.OrgID)
+	diags = setWithError(d, "tokens", tokens, diags)
+	return diags
+}
@@ -0,0 +1,40 @@
+package edgedelta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceAPITokensRead(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/orgs/"+testOrgID+"/api_tokens" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[
+			{"token_id": "2", "name": "deploy", "scopes": ["pipelines:write"], "token": "should-not-leak"},
+			{"token_id": "1", "name": "ci", "scopes": ["pipelines:read", "dashboards:read"], "expires_at": "2024-07-01T00:00:00Z"}
+		]`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceAPITokens().Schema, map[string]interface{}{})
+	diags := dataSourceAPITokensRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if n := d.Get("tokens.#").(int); n != 2 {
+		t.Fatalf("expected 2 tokens, got %d", n)
+	}
+	if got := d.Get("tokens.0.name").(string); got != "ci" {
+		t.Errorf("expected the tokens to be sorted by name, got %q first", got)
+	}
+	if got := d.Get("tokens.0.scopes.#").(int); got != 2 {
+		t.Errorf("expected 2 scopes, got %d", got)
+	}
+	if _, ok := dataSourceAPITokens().Schema["tokens"].Elem.(*schema.Resource).Schema["token"]; ok {
+		t.Error("expected the data source to have no token secret attribute")
+	}
+}
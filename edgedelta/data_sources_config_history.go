@@ -0,0 +1,136 @@
+package edgedelta
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceConfigHistory() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceConfigHistoryRead,
+		Description: "Reads the saved versions of an EdgeDelta config.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"conf_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the config.",
+			},
+
+			// Optional
+			"status": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return versions with this status, e.g. \"deployed\".",
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of versions to return. 0 returns all versions.",
+			},
+			"offset": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of versions to skip, after the status filter is applied.",
+			},
+
+			// Computed
+			"versions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Saved versions of the config, newest first.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"version": {
+							// Millisecond timestamp, TypeInt would overflow on 32-bit platforms
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Version (timestamp) of the saved config.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the version.",
+						},
+						"content": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Config content of the version.",
+						},
+						"content_hash": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "SHA-256 hash of the content, hex encoded.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceConfigHistoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	meta := m.(*ProviderMetadata)
+	confID := d.Get("conf_id").(string)
+
+	histories, err := meta.client.GetConfigHistory(ctx, confID)
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not get the config history",
+			Detail:   apiErrorDetail(err),
+		})
+	}
+
+	histories = filterConfigHistory(histories, d.Get("status").(string), d.Get("offset").(int), d.Get("limit").(int))
+	versions := make([]map[string]interface{}, len(histories))
+	for i, h := range histories {
+		versions[i] = map[string]interface{}{
+			"version":      float64(h.Timestamp),
+			"status":       h.Status,
+			"content":      h.Content,
+			"content_hash": contentHash(h.Content),
+		}
+	}
+
+	d.SetId(confID)
+	diags = setWithError(d, "versions", versions, diags)
+	return diags
+}
+
+// filterConfigHistory sorts histories newest first, keeps the ones with the given status
+// (all if empty) and returns at most limit of them (all if 0) starting at offset
+func filterConfigHistory(histories []ConfigHistory, status string, offset, limit int) []ConfigHistory {
+	filtered := make([]ConfigHistory, 0, len(histories))
+	for _, h := range histories {
+		if status == "" || h.Status == status {
+			filtered = append(filtered, h)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Timestamp > filtered[j].Timestamp
+	})
+
+	if offset >= len(filtered) {
+		return nil
+	}
+	filtered = filtered[offset:]
+	if limit > 0 && limit < len(filtered) {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,85 @@
+package edgedelta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFilterConfigHistory(t *testing.T) {
+	histories := []ConfigHistory{
+		{Timestamp: 1000, Status: "deployed"},
+		{Timestamp: 4000, Status: "saved"},
+		{Timestamp: 3000, Status: "deployed"},
+		{Timestamp: 2000, Status: "saved"},
+	}
+	tests := []struct {
+		name   string
+		status string
+		offset int
+		limit  int
+		want   []int64
+	}{
+		{name: "all", want: []int64{4000, 3000, 2000, 1000}},
+		{name: "status", status: "deployed", want: []int64{3000, 1000}},
+		{name: "limit", limit: 1, want: []int64{4000}},
+		{name: "offset and limit", offset: 1, limit: 2, want: []int64{3000, 2000}},
+		{name: "status and offset", status: "saved", offset: 1, want: []int64{2000}},
+		{name: "offset past the end", offset: 10, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterConfigHistory(histories, tt.status, tt.offset, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d versions, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i, h := range got {
+				if h.Timestamp != tt.want[i] {
+					t.Errorf("versions[%d]: expected %d, got %d", i, tt.want[i], h.Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestDataSourceConfigHistoryRead(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/orgs/"+testOrgID+"/pipelines/"+testConfigID+"/history" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[
+			{"timestamp": 1718000001000, "status": "saved", "content": "b: 2"},
+			{"timestamp": 1718000000000, "status": "deployed", "content": "a: 1"}
+		]`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceConfigHistory().Schema, map[string]interface{}{
+		"conf_id": testConfigID,
+		"status":  "deployed",
+	})
+
+	diags := dataSourceConfigHistoryRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != testConfigID {
+		t.Errorf("expected ID %q, got %q", testConfigID, d.Id())
+	}
+	if n := d.Get("versions.#").(int); n != 1 {
+		t.Fatalf("expected 1 version, got %d", n)
+	}
+	if got := int64(d.Get("versions.0.version").(float64)); got != 1718000000000 {
+		t.Errorf("expected version 1718000000000, got %d", got)
+	}
+	if got := d.Get("versions.0.content").(string); got != "a: 1" {
+		t.Errorf("expected content %q, got %q", "a: 1", got)
+	}
+	// sha256 of "a: 1"
+	const wantHash = "16c3c6d78678d53d39ab2c7a5a7bc4596567b46a1a57000a2d707334f779b824"
+	if got := d.Get("versions.0.content_hash").(string); got != wantHash {
+		t.Errorf("expected content hash %q, got %q", wantHash, got)
+	}
+}
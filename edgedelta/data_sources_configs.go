@@ -0,0 +1,177 @@
+package edgedelta
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceConfigs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceConfigsRead,
+		Description: "Lists the EdgeDelta configs of the organization, optionally filtered.",
+		Schema: map[string]*schema.Schema{
+			// Optional
+			"tag": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateGlob,
+				Description:  "Only return configs whose tag matches this glob pattern, e.g. \"prod-*\".",
+			},
+			"environment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return configs with this environment, e.g. \"Kubernetes\".",
+			},
+			"fleet_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return configs with this fleet type, e.g. \"Edge\".",
+			},
+			"cluster_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return configs with this cluster name.",
+			},
+			"description_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return configs whose description matches this regular expression.",
+			},
+
+			// Computed
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the matching configs, sorted by tag.",
+			},
+			"configs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching configs, sorted by tag.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":            {Type: schema.TypeString, Computed: true, Description: "ID of the config."},
+						"tag":           {Type: schema.TypeString, Computed: true, Description: "Tag of the config."},
+						"environment":   {Type: schema.TypeString, Computed: true, Description: "Environment of the config."},
+						"fleet_type":    {Type: schema.TypeString, Computed: true, Description: "Fleet type of the config."},
+						"fleet_subtype": {Type: schema.TypeString, Computed: true, Description: "Fleet subtype of the config."},
+						"cluster_name":  {Type: schema.TypeString, Computed: true, Description: "Cluster name of the config."},
+						"description":   {Type: schema.TypeString, Computed: true, Description: "Description of the config."},
+					},
+				},
+			},
+		},
+	}
+}
+
+// configFilter selects configs by their metadata, empty fields match everything
+type configFilter struct {
+	tagGlob          string
+	environment      string
+	fleetType        string
+	clusterName      string
+	descriptionRegex *regexp.Regexp
+}
+
+func (f configFilter) match(c *Config) bool {
+	if f.tagGlob != "" {
+		if ok, _ := path.Match(f.tagGlob, c.Tag); !ok {
+			return false
+		}
+	}
+	if f.environment != "" && string(c.Environment) != f.environment {
+		return false
+	}
+	if f.fleetType != "" && string(c.FleetType) != f.fleetType {
+		return false
+	}
+	if f.clusterName != "" && c.ClusterName != f.clusterName {
+		return false
+	}
+	if f.descriptionRegex != nil && !f.descriptionRegex.MatchString(c.Description) {
+		return false
+	}
+	return true
+}
+
+func dataSourceConfigsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	meta := m.(*ProviderMetadata)
+
+	filter := configFilter{
+		tagGlob:     d.Get("tag").(string),
+		environment: d.Get("environment").(string),
+		fleetType:   d.Get("fleet_type").(string),
+		clusterName: d.Get("cluster_name").(string),
+	}
+	if expr := d.Get("description_regex").(string); expr != "" {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Invalid description_regex",
+				Detail:   err.Error(),
+			})
+		}
+		filter.descriptionRegex = re
+	}
+
+	confs, err := meta.client.GetAllConfigs(ctx)
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not get the configs from API",
+			Detail:   apiErrorDetail(err),
+		})
+	}
+
+	var matched []*Config
+	for _, c := range confs {
+		if c != nil && filter.match(c) {
+			matched = append(matched, c)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Tag != matched[j].Tag {
+			return matched[i].Tag < matched[j].Tag
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	ids := make([]string, len(matched))
+	configs := make([]map[string]interface{}, len(matched))
+	for i, c := range matched {
+		ids[i] = c.ID
+		configs[i] = map[string]interface{}{
+			"id":            c.ID,
+			"tag":           c.Tag,
+			"environment":   string(c.Environment),
+			"fleet_type":    string(c.FleetType),
+			"fleet_subtype": string(c.FleetSubtype),
+			"cluster_name":  c.ClusterName,
+			"description":   c.Description,
+		}
+	}
+
+	// The ID identifies the filter, so that different lookups of the same org don't collide
+	d.SetId(contentHash(strings.Join([]string{
+		meta.client.OrgID,
+		filter.tagGlob,
+		filter.environment,
+		filter.fleetType,
+		filter.clusterName,
+		d.Get("description_regex").(string),
+	}, "\n")))
+	diags = setWithError(d, "ids", stringSliceToInterface(ids), diags)
+	diags = setWithError(d, "configs", configs, diags)
+	return diags
+}
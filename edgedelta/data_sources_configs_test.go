@@ -0,0 +1,70 @@
+package edgedelta
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceConfigsRead(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/orgs/"+testOrgID+"/confs" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[
+			{"id": "3", "tag": "prod-eu", "environment": "Kubernetes", "fleet_type": "Edge", "cluster_name": "eu", "description": "EU gateway"},
+			{"id": "1", "tag": "prod-us", "environment": "Kubernetes", "fleet_type": "Edge", "cluster_name": "us", "description": "US edge"},
+			{"id": "2", "tag": "dev", "environment": "Linux", "fleet_type": "Cloud", "description": "sandbox"},
+			{"id": "4", "tag": "prod-legacy", "environment": "Linux", "fleet_type": "Edge", "cluster_name": "eu"}
+		]`))
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantIDs []string
+	}{
+		{name: "no filter", raw: map[string]interface{}{}, wantIDs: []string{"2", "3", "4", "1"}},
+		{name: "tag glob", raw: map[string]interface{}{"tag": "prod-*"}, wantIDs: []string{"3", "4", "1"}},
+		{name: "environment", raw: map[string]interface{}{"environment": "Kubernetes"}, wantIDs: []string{"3", "1"}},
+		{name: "fleet type", raw: map[string]interface{}{"fleet_type": "Cloud"}, wantIDs: []string{"2"}},
+		{name: "cluster name and environment", raw: map[string]interface{}{"cluster_name": "eu", "environment": "Kubernetes"}, wantIDs: []string{"3"}},
+		{name: "description regex", raw: map[string]interface{}{"description_regex": "(?i)^(eu|us) "}, wantIDs: []string{"3", "1"}},
+		{name: "no match", raw: map[string]interface{}{"tag": "staging-*"}, wantIDs: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataSourceConfigs().Schema, tt.raw)
+			diags := dataSourceConfigsRead(context.Background(), d, newTestProviderMetadata(server.URL))
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if d.Id() == "" {
+				t.Error("expected the ID to be set")
+			}
+			ids := make([]string, 0)
+			for _, id := range d.Get("ids").([]interface{}) {
+				ids = append(ids, id.(string))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("expected IDs %v, got %v", tt.wantIDs, ids)
+			}
+			if n := d.Get("configs.#").(int); n != len(tt.wantIDs) {
+				t.Errorf("expected %d configs, got %d", len(tt.wantIDs), n)
+			}
+		})
+	}
+}
+
+func TestValidateGlob(t *testing.T) {
+	if _, errs := validateGlob("prod-*", "tag"); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if _, errs := validateGlob("prod-[", "tag"); len(errs) == 0 {
+		t.Error("expected an error for an invalid glob")
+	}
+}
@@ -0,0 +1,31 @@
+package edgedelta
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Prevent the resource from being destroyed. Set it to false and apply before destroying the resource.",
+	}
+}
+
+// checkDeletionProtection returns an error diagnostic if deletion_protection is enabled in the
+// state. Delete only sees the state, so the protection can only be lifted by applying false first.
+func checkDeletionProtection(d *schema.ResourceData, resourceType string) diag.Diagnostics {
+	if !d.Get("deletion_protection").(bool) {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Cannot destroy %s with deletion protection", resourceType),
+		Detail: fmt.Sprintf("%s '%s' has deletion_protection enabled. To destroy it, set deletion_protection = false, "+
+			"run terraform apply, then destroy it again.", resourceType, d.Id()),
+	}}
+}
@@ -0,0 +1,143 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// validatePipelineGraph checks the node/link graph of a v3 pipeline.
+// Links that reference missing nodes and cycles are errors, since the agent can't run such a
+// pipeline. Orphaned nodes, sources without outgoing links and destinations that no source
+// reaches are warnings: they are valid but usually a mistake.
+func validatePipelineGraph(p *pipeline) (warns []string, errs []error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	nodes := make(map[string]pipelineNode, len(p.Nodes))
+	for _, n := range p.Nodes {
+		nodes[n.Name] = n
+	}
+
+	outgoing := make(map[string][]string, len(p.Nodes))
+	incoming := make(map[string]int, len(p.Nodes))
+	for i, l := range p.Links {
+		valid := true
+		if _, ok := nodes[l.From]; !ok {
+			errs = append(errs, fmt.Errorf("links[%d].from: node %q does not exist", i, l.From))
+			valid = false
+		}
+		if _, ok := nodes[l.To]; !ok {
+			errs = append(errs, fmt.Errorf("links[%d].to: node %q does not exist", i, l.To))
+			valid = false
+		}
+		if valid {
+			outgoing[l.From] = append(outgoing[l.From], l.To)
+			incoming[l.To]++
+		}
+	}
+
+	for _, cycle := range findPipelineCycles(p.Nodes, outgoing) {
+		errs = append(errs, fmt.Errorf("links form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+
+	// Nodes that nothing flows into are where data enters the pipeline
+	var sources []string
+	for _, n := range p.Nodes {
+		if n.Kind == InputNodeKind || (n.Kind == "" && incoming[n.Name] == 0) {
+			sources = append(sources, n.Name)
+		}
+	}
+	reachable := make(map[string]bool, len(p.Nodes))
+	queue := append([]string(nil), sources...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reachable[name] {
+			continue
+		}
+		reachable[name] = true
+		queue = append(queue, outgoing[name]...)
+	}
+
+	for _, n := range p.Nodes {
+		switch {
+		case n.Kind == InputNodeKind && len(outgoing[n.Name]) == 0:
+			warns = append(warns, fmt.Sprintf("%s: source node %q has no outgoing links", n.Path, n.Name))
+		case len(outgoing[n.Name]) == 0 && incoming[n.Name] == 0:
+			warns = append(warns, fmt.Sprintf("%s: node %q is not linked to any other node", n.Path, n.Name))
+		case n.Kind == OutputNodeKind && !reachable[n.Name]:
+			warns = append(warns, fmt.Sprintf("%s: destination node %q is not reachable from any source", n.Path, n.Name))
+		}
+	}
+
+	return warns, errs
+}
+
+// findPipelineCycles returns every cycle found by a depth-first search over the links,
+// each one as the list of node names along the cycle (the first node repeated at the end)
+func findPipelineCycles(nodes []pipelineNode, outgoing map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(nodes))
+	var stack []string
+	var cycles [][]string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = inProgress
+		stack = append(stack, name)
+		for _, next := range outgoing[name] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case inProgress:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						cycle := append(append([]string(nil), stack[i:]...), next)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+
+	for _, n := range nodes {
+		if state[n.Name] == unvisited {
+			visit(n.Name)
+		}
+	}
+	return cycles
+}
+
+// customizeDiffPipelineGraph fails the plan if the node/link graph of config_content is broken
+func customizeDiffPipelineGraph(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("config_content") || !d.NewValueKnown("config_content") {
+		return nil
+	}
+	pipelines, _, errs := parsePipeline(d.Get("config_content").(string))
+	if len(errs) > 0 {
+		// Reported by validatePipelineYAML
+		return nil
+	}
+	var msgs []string
+	for _, p := range pipelines {
+		_, graphErrs := validatePipelineGraph(p)
+		for _, err := range graphErrs {
+			msgs = append(msgs, "  - "+documentPrefix(p.Document)+err.Error())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid pipeline graph in config_content:\n%s", strings.Join(msgs, "\n"))
+}
@@ -0,0 +1,163 @@
+package edgedelta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePipelineGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantErrs  []string
+		wantWarns []string
+	}{
+		{
+			name:    "valid pipeline",
+			content: testValidPipeline,
+		},
+		{
+			name: "dangling references",
+			content: `version: v3
+settings:
+  tag: prod
+nodes:
+  - name: in
+    type: memory_input
+  - name: out
+    type: ed_output
+links:
+  - from: in
+    to: missing_output
+  - from: missing_input
+    to: out
+  - from: in
+    to: out
+`,
+			wantErrs: []string{
+				`links[0].to: node "missing_output" does not exist`,
+				`links[1].from: node "missing_input" does not exist`,
+			},
+		},
+		{
+			name: "cycle",
+			content: `version: v3
+settings:
+  tag: prod
+nodes:
+  - name: in
+    type: memory_input
+  - name: a
+    type: dedup
+  - name: b
+    type: dedup
+  - name: out
+    type: ed_output
+links:
+  - from: in
+    to: a
+  - from: a
+    to: b
+  - from: b
+    to: a
+  - from: b
+    to: out
+`,
+			wantErrs: []string{"links form a cycle: a -> b -> a"},
+		},
+		{
+			name: "orphaned node, idle source and unreachable destination",
+			content: `version: v3
+settings:
+  tag: prod
+nodes:
+  - name: in
+    type: memory_input
+  - name: lonely
+    type: dedup
+  - name: idle
+    type: docker_input
+  - name: filter
+    type: dedup
+  - name: out
+    type: ed_output
+  - name: dead_end
+    type: ed_debug_output
+links:
+  - from: in
+    to: out
+  - from: filter
+    to: dead_end
+`,
+			wantWarns: []string{
+				`nodes[1]: node "lonely" is not linked to any other node`,
+				`nodes[2]: source node "idle" has no outgoing links`,
+				`nodes[5]: destination node "dead_end" is not reachable from any source`,
+			},
+		},
+		{
+			name: "destination fed only by an unlinked processor",
+			content: `version: v3
+settings:
+  tag: prod
+nodes:
+  - name: in
+    type: memory_input
+  - name: out
+    type: ed_output
+  - name: proc
+    type: dedup
+  - name: other_in
+    type: docker_input
+  - name: archive
+    type: ed_archive_output
+links:
+  - from: in
+    to: out
+  - from: other_in
+    to: proc
+  - from: proc
+    to: out
+  - from: archive
+    to: proc
+`,
+			wantWarns: []string{`nodes[4]: destination node "archive" is not reachable from any source`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipelines, _, parseErrs := parsePipeline(tt.content)
+			if len(parseErrs) > 0 {
+				t.Fatalf("unexpected parse errors: %v", parseErrs)
+			}
+			if len(pipelines) != 1 {
+				t.Fatalf("expected 1 pipeline, got %d", len(pipelines))
+			}
+			warns, errs := validatePipelineGraph(pipelines[0])
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.wantErrs), len(errs), errs)
+			}
+			for i, want := range tt.wantErrs {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("expected error %d to contain %q, got %q", i, want, errs[i].Error())
+				}
+			}
+			if len(warns) != len(tt.wantWarns) {
+				t.Fatalf("expected %d warnings, got %d: %v", len(tt.wantWarns), len(warns), warns)
+			}
+			for i, want := range tt.wantWarns {
+				if !strings.Contains(warns[i], want) {
+					t.Errorf("expected warning %d to contain %q, got %q", i, want, warns[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePipelineGraph_NilPipeline(t *testing.T) {
+	warns, errs := validatePipelineGraph(nil)
+	if len(warns) != 0 || len(errs) != 0 {
+		t.Errorf("expected no warnings or errors, got %v, %v", warns, errs)
+	}
+}
@@ -0,0 +1,358 @@
+package edgedelta
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed pipeline_schema.yaml
+var pipelineSchemaYAML []byte
+
+// NodeKind is the role of a pipeline node in the data flow
+type NodeKind string
+
+const (
+	InputNodeKind     NodeKind = "input"
+	ProcessorNodeKind NodeKind = "processor"
+	OutputNodeKind    NodeKind = "output"
+)
+
+type fieldSet struct {
+	Required []string `yaml:"required"`
+	Optional []string `yaml:"optional"`
+}
+
+type nodeTypeSchema struct {
+	Kind     NodeKind `yaml:"kind"`
+	Required []string `yaml:"required"`
+}
+
+// pipelineSchema is the embedded description of the v3 pipeline format (see pipeline_schema.yaml)
+type pipelineSchema struct {
+	Version   string                    `yaml:"version"`
+	Root      fieldSet                  `yaml:"root"`
+	Settings  fieldSet                  `yaml:"settings"`
+	Node      fieldSet                  `yaml:"node"`
+	Link      fieldSet                  `yaml:"link"`
+	NodeTypes map[string]nodeTypeSchema `yaml:"node_types"`
+}
+
+var (
+	loadedPipelineSchema    *pipelineSchema
+	loadedPipelineSchemaErr error
+	loadPipelineSchemaOnce  sync.Once
+)
+
+func loadPipelineSchema() (*pipelineSchema, error) {
+	loadPipelineSchemaOnce.Do(func() {
+		var s pipelineSchema
+		if err := yaml.Unmarshal(pipelineSchemaYAML, &s); err != nil {
+			loadedPipelineSchemaErr = fmt.Errorf("failed to load the embedded pipeline schema: %v", err)
+			return
+		}
+		loadedPipelineSchema = &s
+	})
+	return loadedPipelineSchema, loadedPipelineSchemaErr
+}
+
+// pipelineNode is a single entry of the "nodes" list
+type pipelineNode struct {
+	Name string
+	Type string
+	Kind NodeKind // empty if the node type is unknown to the schema
+	Path string
+}
+
+// pipelineLink is a single entry of the "links" list
+type pipelineLink struct {
+	From string
+	To   string
+	Path string
+}
+
+// pipeline is the subset of a v3 pipeline that the provider inspects
+type pipeline struct {
+	Version  string
+	Document int // 1-based position in a multi-document file, 0 if it is the only document
+	Nodes    []pipelineNode
+	Links    []pipelineLink
+}
+
+// pipelineSchemaError is a schema violation located at a path inside the pipeline YAML
+type pipelineSchemaError struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func (e *pipelineSchemaError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", e.Path, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// parsePipeline parses every document of content and checks the v3 pipelines among them against
+// the embedded pipeline schema. Documents that are not v3 pipelines (e.g. legacy agent configs)
+// are only checked for YAML syntax and have no entry in the returned pipelines. Warnings are
+// returned for constructs that the schema doesn't know about but that are not necessarily
+// wrong, such as unknown node types. In a multi-document file, messages name the document.
+func parsePipeline(content string) ([]*pipeline, []string, []error) {
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, []error{fmt.Errorf("invalid YAML: %v", err)}
+		}
+		// Empty documents, e.g. after a trailing "---", are skipped
+		if doc.Kind == 0 || len(doc.Content) == 0 || doc.Content[0].ShortTag() == "!!null" {
+			continue
+		}
+		docs = append(docs, &doc)
+	}
+
+	var (
+		pipelines []*pipeline
+		warns     []string
+		errs      []error
+	)
+	for i, doc := range docs {
+		docNumber := 0
+		if len(docs) > 1 {
+			docNumber = i + 1
+		}
+		p, docWarns, docErrs := parsePipelineDocument(doc.Content[0])
+		for _, w := range docWarns {
+			warns = append(warns, documentPrefix(docNumber)+w)
+		}
+		for _, err := range docErrs {
+			if docNumber > 0 {
+				err = fmt.Errorf("%s%w", documentPrefix(docNumber), err)
+			}
+			errs = append(errs, err)
+		}
+		if p != nil {
+			p.Document = docNumber
+			pipelines = append(pipelines, p)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, warns, errs
+	}
+	return pipelines, warns, nil
+}
+
+// documentPrefix returns the prefix of the messages about a document of a multi-document
+// file, or "" for the only document
+func documentPrefix(docNumber int) string {
+	if docNumber == 0 {
+		return ""
+	}
+	return fmt.Sprintf("document %d: ", docNumber)
+}
+
+// parsePipelineDocument checks the root node of a single YAML document, see parsePipeline
+func parsePipelineDocument(root *yaml.Node) (*pipeline, []string, []error) {
+	root = resolveAlias(root)
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, []error{&pipelineSchemaError{Path: "$", Line: root.Line, Message: "must be a mapping"}}
+	}
+
+	schema, err := loadPipelineSchema()
+	if err != nil {
+		return nil, nil, []error{err}
+	}
+
+	versionNode := mappingValue(root, "version")
+	if versionNode == nil {
+		// Only treat the content as a v3 pipeline if it looks like one
+		if mappingValue(root, "nodes") == nil {
+			return nil, nil, nil
+		}
+		return nil, nil, []error{&pipelineSchemaError{Path: "$", Line: root.Line, Message: `"version" is required`}}
+	}
+	if versionNode.Kind != yaml.ScalarNode {
+		return nil, nil, []error{&pipelineSchemaError{Path: "version", Line: versionNode.Line, Message: "must be a string"}}
+	}
+	if versionNode.Value != schema.Version {
+		return nil, nil, nil
+	}
+
+	v := &pipelineValidator{schema: schema}
+	p := &pipeline{Version: versionNode.Value}
+
+	v.checkFields(root, "$", schema.Root)
+
+	if settings := mappingValue(root, "settings"); settings != nil {
+		if settings.Kind != yaml.MappingNode {
+			v.errorf("settings", settings, "must be a mapping")
+		} else {
+			v.checkRequired(settings, "settings", schema.Settings.Required)
+		}
+	}
+
+	if nodes := mappingValue(root, "nodes"); nodes != nil {
+		p.Nodes = v.parseNodes(nodes)
+	}
+	if links := mappingValue(root, "links"); links != nil {
+		p.Links = v.parseLinks(links)
+	}
+
+	if len(v.errs) > 0 {
+		return nil, v.warns, v.errs
+	}
+	return p, v.warns, nil
+}
+
+type pipelineValidator struct {
+	schema *pipelineSchema
+	warns  []string
+	errs   []error
+}
+
+func (v *pipelineValidator) errorf(path string, n *yaml.Node, format string, a ...interface{}) {
+	v.errs = append(v.errs, &pipelineSchemaError{Path: path, Line: n.Line, Message: fmt.Sprintf(format, a...)})
+}
+
+func (v *pipelineValidator) warnf(path string, n *yaml.Node, format string, a ...interface{}) {
+	v.warns = append(v.warns, (&pipelineSchemaError{Path: path, Line: n.Line, Message: fmt.Sprintf(format, a...)}).Error())
+}
+
+// checkFields reports missing required keys as errors and unknown keys as warnings
+func (v *pipelineValidator) checkFields(n *yaml.Node, path string, fields fieldSet) {
+	v.checkRequired(n, path, fields.Required)
+	known := make(map[string]bool, len(fields.Required)+len(fields.Optional))
+	for _, f := range fields.Required {
+		known[f] = true
+	}
+	for _, f := range fields.Optional {
+		known[f] = true
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i]
+		if !known[key.Value] {
+			v.warnf(path, key, "unknown field %q", key.Value)
+		}
+	}
+}
+
+func (v *pipelineValidator) checkRequired(n *yaml.Node, path string, required []string) {
+	for _, f := range required {
+		if mappingValue(n, f) == nil {
+			v.errorf(path, n, "%q is required", f)
+		}
+	}
+}
+
+func (v *pipelineValidator) parseNodes(nodes *yaml.Node) []pipelineNode {
+	if nodes.Kind != yaml.SequenceNode {
+		v.errorf("nodes", nodes, "must be a list")
+		return nil
+	}
+	result := make([]pipelineNode, 0, len(nodes.Content))
+	seen := make(map[string]string, len(nodes.Content))
+	for i, raw := range nodes.Content {
+		n := resolveAlias(raw)
+		path := fmt.Sprintf("nodes[%d]", i)
+		if n.Kind != yaml.MappingNode {
+			v.errorf(path, n, "must be a mapping")
+			continue
+		}
+		v.checkRequired(n, path, v.schema.Node.Required)
+		name := scalarValue(n, "name")
+		if f := mappingValue(n, "name"); f != nil && name == "" {
+			v.errorf(path+".name", f, "must be a non-empty string")
+		}
+		typ := scalarValue(n, "type")
+		if f := mappingValue(n, "type"); f != nil && typ == "" {
+			v.errorf(path+".type", f, "must be a non-empty string")
+		}
+		if name != "" {
+			if prev, ok := seen[name]; ok {
+				v.errorf(path+".name", n, "duplicate node name %q (also used by %s)", name, prev)
+			}
+			seen[name] = path
+		}
+		if typ == "" {
+			continue
+		}
+		node := pipelineNode{Name: name, Type: typ, Path: path}
+		typeSchema, ok := v.schema.NodeTypes[typ]
+		if !ok {
+			v.warnf(path+".type", n, "unknown node type %q, the node can't be validated offline", typ)
+		} else {
+			node.Kind = typeSchema.Kind
+			// The fields of the node types are not verified against every agent version,
+			// so a missing one is only a warning
+			for _, f := range typeSchema.Required {
+				if mappingValue(n, f) == nil {
+					v.warnf(path, n, "%q is expected for %s nodes", f, typ)
+				}
+			}
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+func (v *pipelineValidator) parseLinks(links *yaml.Node) []pipelineLink {
+	if links.Kind != yaml.SequenceNode {
+		v.errorf("links", links, "must be a list")
+		return nil
+	}
+	result := make([]pipelineLink, 0, len(links.Content))
+	for i, raw := range links.Content {
+		l := resolveAlias(raw)
+		path := fmt.Sprintf("links[%d]", i)
+		if l.Kind != yaml.MappingNode {
+			v.errorf(path, l, "must be a mapping")
+			continue
+		}
+		v.checkFields(l, path, v.schema.Link)
+		result = append(result, pipelineLink{
+			From: scalarValue(l, "from"),
+			To:   scalarValue(l, "to"),
+			Path: scalarValue(l, "path"),
+		})
+	}
+	return result
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil if it is not present
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return resolveAlias(n.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// scalarValue returns the trimmed scalar value for key in a mapping node, or "" if it is missing or not a scalar
+func scalarValue(n *yaml.Node, key string) string {
+	v := mappingValue(n, key)
+	if v == nil || v.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return strings.TrimSpace(v.Value)
+}
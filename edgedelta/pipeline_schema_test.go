@@ -0,0 +1,213 @@
+package edgedelta
+
+import (
+	"strings"
+	"testing"
+)
+
+const testValidPipeline = `version: v3
+settings:
+  tag: prod
+nodes:
+  - name: k8s_logs
+    type: kubernetes_input
+    include:
+      - k8s.namespace.name=default
+  - name: errors_only
+    type: regex_filter
+    pattern: error
+  - name: ed_output
+    type: ed_output
+links:
+  - from: k8s_logs
+    to: errors_only
+  - from: errors_only
+    to: ed_output
+`
+
+func TestLoadPipelineSchema(t *testing.T) {
+	s, err := loadPipelineSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version != "v3" {
+		t.Errorf("expected schema version v3, got %q", s.Version)
+	}
+	for name, nt := range s.NodeTypes {
+		switch nt.Kind {
+		case InputNodeKind, ProcessorNodeKind, OutputNodeKind:
+		default:
+			t.Errorf("node type %q has invalid kind %q", name, nt.Kind)
+		}
+	}
+}
+
+func TestParsePipeline(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantErrs  []string
+		wantWarns []string
+		wantNil   bool
+	}{
+		{
+			name:    "valid pipeline",
+			content: testValidPipeline,
+		},
+		{
+			name:     "invalid YAML",
+			content:  "version: v3\nnodes: [\n",
+			wantErrs: []string{"invalid YAML"},
+			wantNil:  true,
+		},
+		{
+			name:     "root is not a mapping",
+			content:  "- a\n- b\n",
+			wantErrs: []string{"$ (line 1): must be a mapping"},
+			wantNil:  true,
+		},
+		{
+			name:    "legacy config is not schema checked",
+			content: "version: v2\ninputs:\n  kubernetes: []\n",
+			wantNil: true,
+		},
+		{
+			name:     "missing version",
+			content:  "settings:\n  tag: prod\nnodes: []\n",
+			wantErrs: []string{`"version" is required`},
+			wantNil:  true,
+		},
+		{
+			name:     "missing settings and tag",
+			content:  "version: v3\nnodes: []\n",
+			wantErrs: []string{`$ (line 1): "settings" is required`},
+			wantNil:  true,
+		},
+		{
+			name:     "missing settings tag",
+			content:  "version: v3\nsettings:\n  log:\n    level: info\nnodes: []\n",
+			wantErrs: []string{`settings (line 3): "tag" is required`},
+			wantNil:  true,
+		},
+		{
+			name:     "nodes is not a list",
+			content:  "version: v3\nsettings:\n  tag: prod\nnodes:\n  a: b\n",
+			wantErrs: []string{"nodes (line 5): must be a list"},
+			wantNil:  true,
+		},
+		{
+			name:     "node without type",
+			content:  "version: v3\nsettings:\n  tag: prod\nnodes:\n  - name: a\n",
+			wantErrs: []string{`nodes[0] (line 5): "type" is required`},
+			wantNil:  true,
+		},
+		{
+			name:      "missing type-specific field is a warning",
+			content:   "version: v3\nsettings:\n  tag: prod\nnodes:\n  - name: f\n    type: regex_filter\n",
+			wantWarns: []string{`nodes[0] (line 5): "pattern" is expected for regex_filter nodes`},
+		},
+		{
+			name:     "duplicate node name",
+			content:  "version: v3\nsettings:\n  tag: prod\nnodes:\n  - name: a\n    type: ed_output\n  - name: a\n    type: ed_output\n",
+			wantErrs: []string{`nodes[1].name (line 7): duplicate node name "a" (also used by nodes[0])`},
+			wantNil:  true,
+		},
+		{
+			name:     "link without to",
+			content:  "version: v3\nsettings:\n  tag: prod\nnodes: []\nlinks:\n  - from: a\n",
+			wantErrs: []string{`links[0] (line 6): "to" is required`},
+			wantNil:  true,
+		},
+		{
+			name:      "unknown node type is a warning",
+			content:   "version: v3\nsettings:\n  tag: prod\nnodes:\n  - name: a\n    type: brand_new_output\n",
+			wantWarns: []string{`nodes[0].type (line 5): unknown node type "brand_new_output"`},
+		},
+		{
+			name:      "unknown top-level field is a warning",
+			content:   "version: v3\nsettings:\n  tag: prod\nnodes: []\nextras: true\n",
+			wantWarns: []string{`$ (line 5): unknown field "extras"`},
+		},
+		{
+			name:     "error in a later document",
+			content:  "version: v3\nsettings:\n  tag: prod\nnodes: []\n---\nversion: v3\nnodes: []\n",
+			wantErrs: []string{`document 2: $ (line 6): "settings" is required`},
+			wantNil:  true,
+		},
+		{
+			name:     "invalid YAML in a later document",
+			content:  "version: v3\nsettings:\n  tag: prod\nnodes: []\n---\nnodes: [\n",
+			wantErrs: []string{"invalid YAML"},
+			wantNil:  true,
+		},
+		{
+			name:      "warning in a later document",
+			content:   "version: v3\nsettings:\n  tag: prod\nnodes: []\n---\nversion: v3\nsettings:\n  tag: prod\nnodes: []\nextras: true\n",
+			wantWarns: []string{`document 2: $ (line 10): unknown field "extras"`},
+		},
+		{
+			name:    "empty documents are skipped",
+			content: "---\n" + testValidPipeline + "---\n",
+		},
+		{
+			name:    "anchored node",
+			content: "version: v3\nsettings:\n  tag: prod\nnodes:\n  - &out\n    name: a\n    type: ed_output\nlinks: []\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipelines, warns, errs := parsePipeline(tt.content)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.wantErrs), len(errs), errs)
+			}
+			for i, want := range tt.wantErrs {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("expected error %d to contain %q, got %q", i, want, errs[i].Error())
+				}
+			}
+			if len(warns) != len(tt.wantWarns) {
+				t.Fatalf("expected %d warnings, got %d: %v", len(tt.wantWarns), len(warns), warns)
+			}
+			for i, want := range tt.wantWarns {
+				if !strings.Contains(warns[i], want) {
+					t.Errorf("expected warning %d to contain %q, got %q", i, want, warns[i])
+				}
+			}
+			if (len(pipelines) == 0) != tt.wantNil {
+				t.Errorf("expected no pipeline = %v, got %v", tt.wantNil, pipelines)
+			}
+		})
+	}
+}
+
+func TestParsePipeline_Graph(t *testing.T) {
+	pipelines, _, errs := parsePipeline(testValidPipeline)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(pipelines) != 1 || pipelines[0].Document != 0 {
+		t.Fatalf("expected the only document to be parsed, got %+v", pipelines)
+	}
+	p := pipelines[0]
+	if len(p.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(p.Nodes))
+	}
+	if p.Nodes[0].Kind != InputNodeKind || p.Nodes[1].Kind != ProcessorNodeKind || p.Nodes[2].Kind != OutputNodeKind {
+		t.Errorf("unexpected node kinds: %+v", p.Nodes)
+	}
+	if len(p.Links) != 2 || p.Links[1].From != "errors_only" || p.Links[1].To != "ed_output" {
+		t.Errorf("unexpected links: %+v", p.Links)
+	}
+}
+
+func TestValidatePipelineYAML_MultiDocument(t *testing.T) {
+	unlinked := "version: v3\nsettings:\n  tag: prod\nnodes:\n  - name: out\n    type: ed_output\n"
+	warns, errs := validatePipelineYAML(testValidPipeline+"---\n"+unlinked, "config_content")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(warns) != 1 || !strings.Contains(warns[0], `document 2: nodes[0]`) {
+		t.Errorf("expected a graph warning about the second document, got %v", warns)
+	}
+}
@@ -2,54 +2,145 @@ package edgedelta
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
-			// Required params
+			// Connection params. Each one falls back to an environment variable and then to the
+			// credentials file profile, see resolveCredentials.
 			"org_id": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Unique organization ID",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(OrgIDEnvVar, nil),
+				Description: "Unique organization ID. Can also be set with the " + OrgIDEnvVar + " environment variable or in a credentials file profile.",
 			},
 			"api_secret": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "API secret",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(APISecretEnvVar, nil),
+				Description: "API secret. Can also be set with the " + APISecretEnvVar + " environment variable or in a credentials file profile.",
 				Sensitive:   true,
 			},
-			// Optional params
 			"api_endpoint": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "https://api.edgedelta.com",
-				Description: "API base URL",
+				DefaultFunc: schema.EnvDefaultFunc(APIEndpointEnvVar, nil),
+				Description: "API base URL. Can also be set with the " + APIEndpointEnvVar + " environment variable or in a credentials file profile. Defaults to " + defaultAPIEndpoint + ".",
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(ProfileEnvVar, nil),
+				Description: "Name of the credentials file profile to read the connection settings from. Can also be set with the " + ProfileEnvVar + " environment variable. Defaults to the 'default' profile, if it exists.",
+			},
+			"credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(CredentialsFileEnvVar, nil),
+				Description: "Path of the credentials file. Can also be set with the " + CredentialsFileEnvVar + " environment variable. Defaults to ~/.edgedelta/credentials.",
+			},
+			// Optional params
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of retries for failed API requests. Transport errors and 5xx responses are retried for idempotent requests, 429 responses for all requests. Set to 0 to disable retries.",
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum time in seconds to wait between two retries, including waits requested by the API with Retry-After",
+			},
+			"parallelism": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of concurrent API requests made by the provider. Set to 0 for no limit.",
+			},
+			"max_idle_conns": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultMaxIdleConns,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of idle keep-alive connections kept open to the API",
+			},
+			"max_conns_per_host": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of open connections to the API. Set to 0 for no limit.",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"edgedelta_config":    resourceConfig(),
-			"edgedelta_dashboard": resourceDashboard(),
+			"edgedelta_api_token":            resourceAPIToken(),
+			"edgedelta_config":               resourceConfig(),
+			"edgedelta_config_deployment":    resourceConfigDeployment(),
+			"edgedelta_dashboard":            resourceDashboard(),
+			"edgedelta_dashboard_access":     resourceDashboardAccess(),
+			"edgedelta_monitor":              resourceMonitor(),
+			"edgedelta_notification_channel": resourceNotificationChannel(),
+			"edgedelta_role":                 resourceRole(),
+			"edgedelta_team":                 resourceTeam(),
+			"edgedelta_user":                 resourceUser(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"edgedelta_api_tokens":     dataSourceAPITokens(),
+			"edgedelta_config_history": dataSourceConfigHistory(),
+			"edgedelta_configs":        dataSourceConfigs(),
 		},
-		DataSourcesMap:       map[string]*schema.Resource{},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
 
 type ProviderMetadata struct {
-	client APIClient
+	client *APIClient
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	creds, err := resolveCredentials(
+		providerCredentials{
+			OrgID:       d.Get("org_id").(string),
+			APISecret:   d.Get("api_secret").(string),
+			APIEndpoint: d.Get("api_endpoint").(string),
+		},
+		d.Get("profile").(string),
+		d.Get("credentials_file").(string),
+	)
+	if err != nil {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Could not configure the Edge Delta provider",
+			Detail:   err.Error(),
+		}}
+	}
 
-	return &ProviderMetadata{
-		client: APIClient{
-			APIBaseURL: d.Get("api_endpoint").(string),
-			OrgID:      d.Get("org_id").(string),
-			apiSecret:  d.Get("api_secret").(string),
+	// A single client is shared by all resources, so that they reuse the same connection pool
+	// and the parallelism limit applies to the provider as a whole
+	client := NewAPIClient(
+		creds.OrgID,
+		creds.APIEndpoint,
+		creds.APISecret,
+		APIClientOptions{
+			MaxRetries:      d.Get("max_retries").(int),
+			RetryMaxWait:    time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+			MaxIdleConns:    d.Get("max_idle_conns").(int),
+			MaxConnsPerHost: d.Get("max_conns_per_host").(int),
+			Parallelism:     d.Get("parallelism").(int),
 		},
+	)
+
+	return &ProviderMetadata{
+		client: client,
 	}, nil
 }
@@ -0,0 +1,303 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("provider schema is invalid: %v", err)
+	}
+}
+
+func TestProviderConfigure(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"org_id":             testOrgID,
+		"api_secret":         testAPISecret,
+		"api_endpoint":       "http://localhost:1234",
+		"max_retries":        5,
+		"retry_max_wait":     10,
+		"parallelism":        4,
+		"max_conns_per_host": 8,
+		"credentials_file":   filepath.Join(t.TempDir(), "credentials"),
+	})
+
+	m, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	client := m.(*ProviderMetadata).client
+	if client.OrgID != testOrgID || client.APIBaseURL != "http://localhost:1234" || client.apiSecret != testAPISecret {
+		t.Errorf("unexpected client credentials: %+v", client)
+	}
+	if client.MaxRetries != 5 || client.RetryMaxWait != 10*time.Second {
+		t.Errorf("unexpected retry settings: %d, %s", client.MaxRetries, client.RetryMaxWait)
+	}
+	if cap(client.sem) != 4 {
+		t.Errorf("expected parallelism 4, got %d", cap(client.sem))
+	}
+	transport := client.cl.Transport.(*http.Transport)
+	if transport.MaxConnsPerHost != 8 || transport.MaxIdleConnsPerHost != defaultMaxIdleConns {
+		t.Errorf("unexpected transport settings: MaxConnsPerHost=%d MaxIdleConnsPerHost=%d", transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+}
+
+// TestParallelResources creates many resources concurrently through a single shared client,
+// the way Terraform does with its default parallelism. Run with -race to catch unsynchronized access.
+func TestParallelResources(t *testing.T) {
+	const (
+		resourceCount = 40
+		parallelism   = 5
+	)
+	var inFlight, maxInFlight, ids int32
+	var conns sync.Map
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			prev := atomic.LoadInt32(&maxInFlight)
+			if current <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, current) {
+				break
+			}
+		}
+		conns.Store(r.RemoteAddr, true)
+		time.Sleep(5 * time.Millisecond)
+
+		id := fmt.Sprintf("00000000-0000-0000-0000-%012d", atomic.AddInt32(&ids, 1))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/dashboards"):
+			_ = json.NewEncoder(w).Encode(Dashboard{DashboardID: id, DashboardName: "dash"})
+		case strings.HasSuffix(r.URL.Path, "/confs"):
+			_ = json.NewEncoder(w).Encode(Config{ID: id, Tag: "tag"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	meta := &ProviderMetadata{
+		client: NewAPIClient(testOrgID, server.URL, testAPISecret, APIClientOptions{Parallelism: parallelism}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < resourceCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				d := schema.TestResourceDataRaw(t, resourceDashboard().Schema, map[string]interface{}{
+					"dashboard_name": fmt.Sprintf("dashboard-%d", i),
+				})
+				if diags := resourceDashboardCreate(context.Background(), d, meta); diags.HasError() {
+					t.Errorf("dashboard %d: unexpected error: %v", i, diags)
+				}
+				if d.Id() == "" {
+					t.Errorf("dashboard %d: expected an ID to be set", i)
+				}
+				return
+			}
+			d := schema.TestResourceDataRaw(t, resourceConfig().Schema, map[string]interface{}{
+				"config_content": fmt.Sprintf("index: %d", i),
+				"environment":    "Linux",
+			})
+			if diags := resourceConfigCreate(context.Background(), d, meta); diags.HasError() {
+				t.Errorf("config %d: unexpected error: %v", i, diags)
+			}
+			if d.Id() == "" {
+				t.Errorf("config %d: expected an ID to be set", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if ids != resourceCount {
+		t.Errorf("expected %d requests, got %d", resourceCount, ids)
+	}
+	if maxInFlight > parallelism {
+		t.Errorf("expected at most %d concurrent requests, got %d", parallelism, maxInFlight)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("expected requests to run concurrently, max in flight was %d", maxInFlight)
+	}
+	connCount := 0
+	conns.Range(func(_, _ interface{}) bool {
+		connCount++
+		return true
+	})
+	if connCount > parallelism {
+		t.Errorf("expected keep-alive connections to be reused, got %d connections", connCount)
+	}
+}
+
+// writeTestCredentialsFile writes a credentials file with a default and a staging profile
+func writeTestCredentialsFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "credentials")
+	content := `# Edge Delta credentials
+[default]
+org_id     = file-default-org
+api_secret = file-default-secret
+
+[staging]
+org_id       = "file-staging-org"
+api_secret   = file-staging-secret
+api_endpoint = https://api.staging.example.com
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write credentials file: %v", err)
+	}
+	return path
+}
+
+func TestProviderConfigure_CredentialsPrecedence(t *testing.T) {
+	credentialsFile := writeTestCredentialsFile(t)
+	tests := []struct {
+		name         string
+		raw          map[string]interface{}
+		env          map[string]string
+		wantOrgID    string
+		wantSecret   string
+		wantEndpoint string
+		wantErr      string
+	}{
+		{
+			name:         "provider block wins over everything",
+			raw:          map[string]interface{}{"org_id": "block-org", "api_secret": "block-secret", "api_endpoint": "https://block.example.com", "profile": "staging"},
+			env:          map[string]string{OrgIDEnvVar: "env-org", APISecretEnvVar: "env-secret", APIEndpointEnvVar: "https://env.example.com"},
+			wantOrgID:    "block-org",
+			wantSecret:   "block-secret",
+			wantEndpoint: "https://block.example.com",
+		},
+		{
+			name:         "environment wins over the credentials file",
+			raw:          map[string]interface{}{"profile": "staging"},
+			env:          map[string]string{OrgIDEnvVar: "env-org", APISecretEnvVar: "env-secret"},
+			wantOrgID:    "env-org",
+			wantSecret:   "env-secret",
+			wantEndpoint: "https://api.staging.example.com",
+		},
+		{
+			name:         "named profile",
+			raw:          map[string]interface{}{"profile": "staging"},
+			wantOrgID:    "file-staging-org",
+			wantSecret:   "file-staging-secret",
+			wantEndpoint: "https://api.staging.example.com",
+		},
+		{
+			name:         "profile from the environment",
+			env:          map[string]string{ProfileEnvVar: "staging"},
+			wantOrgID:    "file-staging-org",
+			wantSecret:   "file-staging-secret",
+			wantEndpoint: "https://api.staging.example.com",
+		},
+		{
+			name:         "default profile and default endpoint",
+			wantOrgID:    "file-default-org",
+			wantSecret:   "file-default-secret",
+			wantEndpoint: defaultAPIEndpoint,
+		},
+		{
+			name:         "mix of block, environment and profile",
+			raw:          map[string]interface{}{"org_id": "block-org"},
+			env:          map[string]string{APIEndpointEnvVar: "https://env.example.com"},
+			wantOrgID:    "block-org",
+			wantSecret:   "file-default-secret",
+			wantEndpoint: "https://env.example.com",
+		},
+		{
+			name:    "unknown profile",
+			raw:     map[string]interface{}{"profile": "production"},
+			wantErr: "profile 'production' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{OrgIDEnvVar, APISecretEnvVar, APIEndpointEnvVar, ProfileEnvVar} {
+				t.Setenv(k, "")
+			}
+			t.Setenv(CredentialsFileEnvVar, credentialsFile)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			raw := tt.raw
+			if raw == nil {
+				raw = map[string]interface{}{}
+			}
+			d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+			m, diags := providerConfigure(context.Background(), d)
+			if tt.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Detail, tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			client := m.(*ProviderMetadata).client
+			if client.OrgID != tt.wantOrgID {
+				t.Errorf("expected org_id %q, got %q", tt.wantOrgID, client.OrgID)
+			}
+			if client.apiSecret != tt.wantSecret {
+				t.Errorf("expected api_secret %q, got %q", tt.wantSecret, client.apiSecret)
+			}
+			if client.APIBaseURL != tt.wantEndpoint {
+				t.Errorf("expected api_endpoint %q, got %q", tt.wantEndpoint, client.APIBaseURL)
+			}
+		})
+	}
+}
+
+func TestProviderConfigure_MissingCredentials(t *testing.T) {
+	for _, k := range []string{OrgIDEnvVar, APISecretEnvVar, APIEndpointEnvVar, ProfileEnvVar} {
+		t.Setenv(k, "")
+	}
+	t.Setenv(CredentialsFileEnvVar, filepath.Join(t.TempDir(), "missing"))
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"org_id": testOrgID})
+	_, diags := providerConfigure(context.Background(), d)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "api_secret is required") {
+		t.Fatalf("expected a missing api_secret error, got %v", diags)
+	}
+}
+
+func TestLoadCredentialsProfile_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("[default]\norg_id\n"), 0600); err != nil {
+		t.Fatalf("failed to write credentials file: %v", err)
+	}
+	if _, err := loadCredentialsProfile(path, "default"); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected a parse error for line 2, got %v", err)
+	}
+}
+
+func TestNewAPIClientFromEnvironment_Options(t *testing.T) {
+	t.Setenv(OrgIDEnvVar, testOrgID)
+	t.Setenv(APISecretEnvVar, testAPISecret)
+	t.Setenv(CredentialsFileEnvVar, filepath.Join(t.TempDir(), "missing"))
+
+	client, err := NewAPIClientFromEnvironment("", "", APIClientOptions{MaxRetries: 0, RetryMaxWait: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.OrgID != testOrgID {
+		t.Errorf("expected org ID %q, got %q", testOrgID, client.OrgID)
+	}
+	if client.MaxRetries != 0 || client.RetryMaxWait != 5*time.Second {
+		t.Errorf("unexpected retry settings: %d, %s", client.MaxRetries, client.RetryMaxWait)
+	}
+}
@@ -0,0 +1,242 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAPIToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAPITokenCreate,
+		ReadContext:   resourceAPITokenRead,
+		UpdateContext: resourceAPITokenUpdate,
+		DeleteContext: resourceAPITokenDelete,
+		CustomizeDiff: customizeDiffAPITokenRotation,
+		Description:   "Manages an EdgeDelta API token. The token is replaced whenever one of its arguments changes.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the API token.",
+			},
+			"scopes": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotWhiteSpace},
+				Description: "Permission scopes granted to the token, e.g. \"pipelines:read\".",
+			},
+
+			// Optional
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Description of the API token.",
+			},
+			"expires_in": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Lifetime of the token from its creation, as a duration (e.g. \"720h\"). The token never expires if unset.",
+			},
+			"rotate_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Replace the token on the first plan this long before it expires (e.g. \"168h\"). Without it, the token is replaced once it has expired.",
+			},
+			"keepers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that replace the token when they change.",
+			},
+
+			// Computed
+			"token_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Unique identifier for the API token.",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Secret value of the API token, only known to the Terraform run that created it and stored in state.",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp after which the token is no longer valid.",
+			},
+			"ready_for_rotation": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the token is within rotate_before of its expiry, or has expired, as of the last refresh.",
+			},
+			"creator": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "User ID who created the API token.",
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of API token creation.",
+			},
+			"last_used": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of the last request made with the token.",
+			},
+		},
+	}
+}
+
+// apiTokenRotationDue reports whether a token expiring at expiresAt must be rotated at now.
+// Tokens without expiry are never rotated.
+func apiTokenRotationDue(expiresAt, rotateBefore string, now time.Time) (bool, error) {
+	if expiresAt == "" {
+		return false, nil
+	}
+	expiry, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the expiry '%s': %s", expiresAt, err)
+	}
+	var before time.Duration
+	if rotateBefore != "" {
+		if before, err = time.ParseDuration(rotateBefore); err != nil {
+			return false, fmt.Errorf("failed to parse rotate_before '%s': %s", rotateBefore, err)
+		}
+	}
+	return !now.Before(expiry.Add(-before)), nil
+}
+
+// customizeDiffAPITokenRotation plans the replacement of tokens that are due for rotation
+func customizeDiffAPITokenRotation(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	due, err := apiTokenRotationDue(d.Get("expires_at").(string), d.Get("rotate_before").(string), time.Now())
+	if err != nil || !due {
+		return err
+	}
+	// The replacement gets a new expiry, which forces the new token
+	if err := d.SetNewComputed("expires_at"); err != nil {
+		return err
+	}
+	return d.ForceNew("expires_at")
+}
+
+func setAPITokenState(d *schema.ResourceData, token *APIToken, diags diag.Diagnostics) diag.Diagnostics {
+	diags = setWithError(d, "token_id", token.TokenID, diags)
+	diags = setWithError(d, "name", token.Name, diags)
+	diags = setWithError(d, "description", token.Description, diags)
+	diags = setWithError(d, "scopes", stringSliceToInterface(token.Scopes), diags)
+	diags = setWithError(d, "expires_at", token.ExpiresAt, diags)
+	diags = setWithError(d, "creator", token.Creator, diags)
+	diags = setWithError(d, "created", token.Created, diags)
+	diags = setWithError(d, "last_used", token.LastUsed, diags)
+
+	due, err := apiTokenRotationDue(token.ExpiresAt, d.Get("rotate_before").(string), time.Now())
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Could not determine whether the API token is due for rotation",
+			Detail:   err.Error(),
+		})
+	}
+	return setWithError(d, "ready_for_rotation", due, diags)
+}
+
+func resourceAPITokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	token := &APIToken{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Scopes:      interfaceSliceToStringSlice(d.Get("scopes").(*schema.Set).List()),
+	}
+	if v, ok := d.GetOk("expires_in"); ok {
+		// Validated by validateDuration
+		expiresIn, _ := time.ParseDuration(v.(string))
+		token.ExpiresAt = time.Now().UTC().Add(expiresIn).Format(time.RFC3339)
+	}
+
+	resp, err := meta.client.CreateAPIToken(ctx, token)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not create the API token resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId(resp.TokenID)
+	// The secret is only part of the create response, it is never read back
+	diags = setWithError(d, "token", resp.Token, diags)
+	return setAPITokenState(d, (*APIToken)(resp), diags)
+}
+
+func resourceAPITokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.GetAPIToken(ctx, d.Id())
+	if err != nil {
+		// The token was revoked outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not read the API token resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	return setAPITokenState(d, (*APIToken)(resp), diags)
+}
+
+// resourceAPITokenUpdate only handles rotate_before, every other argument replaces the token
+func resourceAPITokenUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceAPITokenRead(ctx, d, m)
+}
+
+func resourceAPITokenDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	err := meta.client.DeleteAPIToken(ctx, d.Id())
+	if err != nil {
+		// If already revoked, just remove from state
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not revoke the API token",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,186 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAPITokenRotationDue(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		expiresAt    string
+		rotateBefore string
+		want         bool
+		wantErr      bool
+	}{
+		{name: "no expiry", want: false},
+		{name: "no expiry with rotate_before", rotateBefore: "24h", want: false},
+		{name: "not expired", expiresAt: "2024-06-02T12:00:00Z", want: false},
+		{name: "expired", expiresAt: "2024-06-01T11:00:00Z", want: true},
+		{name: "outside rotation window", expiresAt: "2024-06-03T12:00:00Z", rotateBefore: "24h", want: false},
+		{name: "inside rotation window", expiresAt: "2024-06-02T11:00:00Z", rotateBefore: "24h", want: true},
+		{name: "start of rotation window", expiresAt: "2024-06-02T12:00:00Z", rotateBefore: "24h", want: true},
+		{name: "invalid expiry", expiresAt: "tomorrow", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apiTokenRotationDue(tt.expiresAt, tt.rotateBefore, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResourceAPITokenDiff_Rotation(t *testing.T) {
+	state := func(expiresAt string) *terraform.InstanceState {
+		scopeHash := strconv.Itoa(schema.HashString("pipelines:read"))
+		return &terraform.InstanceState{
+			ID: testTokenID,
+			Attributes: map[string]string{
+				"id":                  testTokenID,
+				"token_id":            testTokenID,
+				"name":                "ci",
+				"scopes.#":            "1",
+				"scopes." + scopeHash: "pipelines:read",
+				"expires_in":          "720h",
+				"rotate_before":       "168h",
+				"expires_at":          expiresAt,
+				"token":               "secret",
+			},
+		}
+	}
+	raw := map[string]interface{}{
+		"name":          "ci",
+		"scopes":        []interface{}{"pipelines:read"},
+		"expires_in":    "720h",
+		"rotate_before": "168h",
+	}
+	tests := []struct {
+		name        string
+		expiresAt   string
+		raw         map[string]interface{}
+		wantReplace bool
+	}{
+		{name: "far from expiry", expiresAt: time.Now().Add(30 * 24 * time.Hour).UTC().Format(time.RFC3339), raw: raw},
+		{name: "within rotate_before", expiresAt: time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339), raw: raw, wantReplace: true},
+		{name: "expired", expiresAt: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), raw: raw, wantReplace: true},
+		{
+			name:        "keepers changed",
+			expiresAt:   time.Now().Add(30 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			raw:         map[string]interface{}{"name": "ci", "scopes": []interface{}{"pipelines:read"}, "expires_in": "720h", "rotate_before": "168h", "keepers": map[string]interface{}{"run": "2"}},
+			wantReplace: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := resourceAPIToken().Diff(context.Background(), state(tt.expiresAt), terraform.NewResourceConfigRaw(tt.raw), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := diff != nil && diff.RequiresNew(); got != tt.wantReplace {
+				t.Errorf("expected replacement: %t, got: %t (%v)", tt.wantReplace, got, diff)
+			}
+		})
+	}
+}
+
+func TestResourceAPITokenCreate(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var token APIToken
+		if err := json.NewDecoder(r.Body).Decode(&token); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		expiry, err := time.Parse(time.RFC3339, token.ExpiresAt)
+		if err != nil || expiry.Before(time.Now().Add(23*time.Hour)) || expiry.After(time.Now().Add(25*time.Hour)) {
+			t.Errorf("expected an expiry in 24h, got %q", token.ExpiresAt)
+		}
+		token.TokenID = testTokenID
+		token.Token = "ed-secret"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(token)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceAPIToken().Schema, map[string]interface{}{
+		"name":          "ci",
+		"scopes":        []interface{}{"pipelines:read"},
+		"expires_in":    "24h",
+		"rotate_before": "48h",
+	})
+
+	diags := resourceAPITokenCreate(context.Background(), d, newTestProviderMetadata(server.URL))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if d.Id() != testTokenID {
+		t.Errorf("expected ID %s, got %q", testTokenID, d.Id())
+	}
+	if got := d.Get("token").(string); got != "ed-secret" {
+		t.Errorf("expected the token secret to be stored, got %q", got)
+	}
+	if !d.Get("ready_for_rotation").(bool) {
+		t.Error("expected a token expiring within rotate_before to be ready for rotation")
+	}
+}
+
+func TestResourceAPITokenRead_KeepsSecret(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token_id": "` + testTokenID + `", "name": "ci", "scopes": ["pipelines:read"], "last_used": "2024-06-01T00:00:00Z"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceAPIToken().Schema, map[string]interface{}{
+		"name":   "ci",
+		"scopes": []interface{}{"pipelines:read"},
+	})
+	d.SetId(testTokenID)
+	_ = d.Set("token", "ed-secret")
+
+	diags := resourceAPITokenRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := d.Get("token").(string); got != "ed-secret" {
+		t.Errorf("expected the token secret to be kept, got %q", got)
+	}
+	if got := d.Get("last_used").(string); got != "2024-06-01T00:00:00Z" {
+		t.Errorf("unexpected last_used %q", got)
+	}
+	if d.Get("ready_for_rotation").(bool) {
+		t.Error("expected a token without expiry to never be ready for rotation")
+	}
+}
+
+func TestResourceAPITokenRead_Revoked(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceAPIToken().Schema, map[string]interface{}{
+		"name":   "ci",
+		"scopes": []interface{}{"pipelines:read"},
+	})
+	d.SetId(testTokenID)
+
+	diags := resourceAPITokenRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the revoked token to be removed from state, got ID %q", d.Id())
+	}
+}
@@ -6,26 +6,45 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceConfig() *schema.Resource {
 	return &schema.Resource{
-		SchemaVersion: 1,
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 1,
+				Type:    resourceConfigV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceConfigStateUpgradeV1,
+			},
+		},
 		CreateContext: resourceConfigCreate,
 		ReadContext:   resourceConfigRead,
 		UpdateContext: resourceConfigUpdate,
 		DeleteContext: resourceConfigDelete,
+		Timeouts:      rolloutTimeouts(),
+		CustomizeDiff: customdiff.All(
+			customizeDiffPipelineGraph,
+			customizeDiffDeployedVersion,
+			customizeDiffLifecycleMode,
+			customizeDiffFleet,
+		),
 		Schema: map[string]*schema.Schema{
 			// Required params
 			"config_content": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Configuration file data",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
+				ValidateFunc:     validatePipelineYAML,
+				Description:      "Configuration file data",
 			},
 			"environment": {
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
 				Description: "Environment where the pipeline will be deployed (Kubernetes, Linux, Windows, MacOS, Docker)",
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					v := val.(string)
@@ -51,6 +70,7 @@ func resourceConfig() *schema.Resource {
 			"fleet_type": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				ForceNew:    true,
 				Default:     string(EdgeFleetType),
 				Description: "Fleet type (Edge, Cloud). Defaults to Edge.",
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
@@ -79,6 +99,7 @@ func resourceConfig() *schema.Resource {
 			"fleet_subtype": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				ForceNew:    true,
 				Description: "Fleet subtype (Edge, Coordinator, Gateway). Required when environment is Kubernetes and fleet_type is Edge.",
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					v := val.(string)
@@ -117,71 +138,134 @@ func resourceConfig() *schema.Resource {
 				Default:     true,
 				Description: "Automatically deploy the config after saving. If false, only saves the config.",
 			},
+			"deployed_version": {
+				// Millisecond timestamp, TypeInt would overflow on 32-bit platforms
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateConfigVersion(1),
+				Description:  "Config history version (timestamp) that is deployed. Computed from the API when not set. Set it to deploy a specific saved version, e.g. to roll back.",
+			},
+			"wait_for_rollout": rolloutSchema(),
+			"lifecycle_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{string(OwnLifecycleMode), string(AdoptLifecycleMode)}, false),
+				Description:  "What happens to the config on destroy. \"own\" deletes it, \"adopt\" leaves it in place or restores it (see restore_on_destroy). Defaults to \"adopt\" when conf_id is set and \"own\" otherwise.",
+			},
+			"deletion_protection": deletionProtectionSchema(),
+			"restore_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Restore the content and description that an adopted config had before Terraform managed it when the resource is destroyed. Only used when lifecycle_mode is \"adopt\".",
+			},
 			// Computed
+			"original_content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Content of an adopted config before Terraform first saved it",
+			},
+			"original_description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description of an adopted config before Terraform first saved it",
+			},
 			"tag": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 		},
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				meta := m.(*ProviderMetadata)
-				confID := d.Id()
-				if confID == "" { // confID DNE
-					return nil, fmt.Errorf("could not determine the resource ID - possibly the ID was not set")
-				}
-				var confIDs []string
-				if confID == "*" {
-					confs, err := meta.client.GetAllConfigs()
-					if err != nil {
-						return nil, fmt.Errorf("could not get the configs from API: %s", err)
-					}
-					results := make([]*schema.ResourceData, 0, len(confs))
-					for _, c := range confs {
-						dd := resourceConfig().Data(nil)
-						dd.SetId(c.ID)
-						if err := dd.Set("conf_id", c.ID); err != nil {
-							return nil, fmt.Errorf("failed to set conf_id: %s", err)
-						}
-						if err := dd.Set("tag", c.Tag); err != nil {
-							return nil, fmt.Errorf("failed to set tag: %s", err)
-						}
-						if err := dd.Set("config_content", c.Content); err != nil {
-							return nil, fmt.Errorf("failed to set config_content: %s", err)
-						}
-						results = append(results, dd)
-					}
-					return results, nil
-				} else if strings.Contains(confID, ",") {
-					confIDs = strings.Split(confID, ",")
-				} else {
-					confIDs = []string{confID}
-				}
-				results := make([]*schema.ResourceData, 0, len(confIDs))
-				for _, id := range confIDs {
-					dd := resourceConfig().Data(nil)
-					resp, err := meta.client.GetConfigWithID(id)
-					if err != nil {
-						return nil, fmt.Errorf("could not get the resource data from API: %s (resource ID was: '%s')", err, id)
-					}
-					dd.SetId(resp.ID)
-					if err := dd.Set("conf_id", id); err != nil {
-						return nil, fmt.Errorf("failed to set conf_id: %s", err)
-					}
-					if err := dd.Set("tag", resp.Tag); err != nil {
-						return nil, fmt.Errorf("failed to set tag: %s", err)
-					}
-					if err := dd.Set("config_content", resp.Content); err != nil {
-						return nil, fmt.Errorf("failed to set config_content: %s", err)
-					}
-					results = append(results, dd)
-				}
-				return results, nil
-			},
+			StateContext: resourceConfigImport,
 		},
 	}
 }
 
+// resourceConfigImport imports a single config ID, a comma-separated list of IDs or every config ("*").
+// Terraform didn't create the imported configs, so they are adopted like configs referenced by conf_id,
+// and their current content is what restore_on_destroy restores.
+func resourceConfigImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	meta := m.(*ProviderMetadata)
+	confID := strings.TrimSpace(d.Id())
+	if confID == "" { // confID DNE
+		return nil, fmt.Errorf("could not determine the resource ID - possibly the ID was not set")
+	}
+
+	var confs []*Config
+	if confID == "*" {
+		var err error
+		confs, err = meta.client.GetAllConfigs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get the configs from API: %s", err)
+		}
+	} else {
+		for _, id := range strings.Split(confID, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			resp, err := meta.client.GetConfigWithID(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("could not get the resource data from API: %s (resource ID was: '%s')", err, id)
+			}
+			confs = append(confs, (*Config)(resp))
+		}
+	}
+
+	results := make([]*schema.ResourceData, 0, len(confs))
+	for _, c := range confs {
+		dd := resourceConfig().Data(nil)
+		dd.SetId(c.ID)
+		var diags diag.Diagnostics
+		diags = setConfigDefaults(dd, diags)
+		diags = setWithError(dd, "conf_id", c.ID, diags)
+		diags = setWithError(dd, "lifecycle_mode", string(AdoptLifecycleMode), diags)
+		diags = setWithError(dd, "original_content", c.Content, diags)
+		diags = setWithError(dd, "original_description", c.Description, diags)
+		diags = setConfigState(dd, c, diags)
+		if len(diags) > 0 {
+			return nil, fmt.Errorf("failed to import config '%s': %s: %s", c.ID, diags[0].Summary, diags[0].Detail)
+		}
+		results = append(results, dd)
+	}
+	return results, nil
+}
+
+// setConfigDefaults sets every attribute that has a schema default. Imported state doesn't get
+// the defaults otherwise, and the first plan after the import would want to set them.
+func setConfigDefaults(d *schema.ResourceData, diags diag.Diagnostics) diag.Diagnostics {
+	for key, s := range resourceConfig().Schema {
+		if s.Default != nil {
+			diags = setWithError(d, key, s.Default, diags)
+		}
+	}
+	return diags
+}
+
+// setConfigState maps the fields of a config returned by the API into the state
+func setConfigState(d *schema.ResourceData, c *Config, diags diag.Diagnostics) diag.Diagnostics {
+	diags = setWithError(d, "tag", c.Tag, diags)
+	// config_content is compared with suppressEquivalentYAML, so formatting-only
+	// differences are not reported as drift
+	diags = setWithError(d, "config_content", c.Content, diags)
+	// environment, fleet_type and fleet_subtype force a replacement, so don't let a response
+	// that omits them plan one
+	if c.Environment != "" {
+		diags = setWithError(d, "environment", string(c.Environment), diags)
+	}
+	if c.FleetType != "" {
+		diags = setWithError(d, "fleet_type", string(c.FleetType), diags)
+	}
+	if c.FleetSubtype != "" {
+		diags = setWithError(d, "fleet_subtype", string(c.FleetSubtype), diags)
+	}
+	diags = setWithError(d, "cluster_name", c.ClusterName, diags)
+	diags = setWithError(d, "description", c.Description, diags)
+	return diags
+}
+
 type configArgs struct {
 	confID       string
 	confData     string
@@ -191,6 +275,7 @@ type configArgs struct {
 	clusterName  string
 	description  string
 	autoDeploy   bool
+	rollout      *rolloutOptions
 	diags        diag.Diagnostics
 }
 
@@ -241,6 +326,15 @@ func parseArgs(d *schema.ResourceData) *configArgs {
 	if autoDeployRaw != nil {
 		args.autoDeploy = autoDeployRaw.(bool)
 	}
+	rollout, err := parseRolloutOptions(d.Get("wait_for_rollout"))
+	if err != nil {
+		args.diags = append(args.diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid wait_for_rollout",
+			Detail:   err.Error(),
+		})
+	}
+	args.rollout = rollout
 	return args
 }
 
@@ -255,46 +349,144 @@ func setWithError(d *schema.ResourceData, key string, value any, diags diag.Diag
 	return diags
 }
 
-func saveAndDeployConfig(client APIClient, confID string, saveReq SaveRequest, autoDeploy bool, errorContext string) (*SaveConfigResponse, diag.Diagnostics) {
+func saveAndDeployConfig(ctx context.Context, client *APIClient, confID string, saveReq SaveRequest, deploy bool, version int64, rollout *rolloutOptions, errorContext string) (*SaveConfigResponse, int64, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	// Step 1: Save the config
-	saveResp, err := client.SaveConfig(confID, saveReq)
+	saveResp, err := client.SaveConfig(ctx, confID, saveReq)
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  fmt.Sprintf("Could not save the config resource%s", errorContext),
-			Detail:   fmt.Sprintf("%s", err),
+			Detail:   apiErrorDetail(err),
 		})
-		return nil, diags
+		return nil, 0, diags
+	}
+
+	// Step 2: Conditionally deploy if auto_deploy is true or a version is pinned
+	if !deploy {
+		return saveResp, 0, diags
 	}
+	deployedVersion, deployDiags := deployConfigVersion(ctx, client, confID, version, rollout)
+	if len(deployDiags) > 0 {
+		diags = append(diags, deployDiags...)
+		return nil, 0, diags
+	}
+
+	return saveResp, deployedVersion, diags
+}
+
+// deployConfigVersion deploys the given history version of the config, or the latest saved
+// version if version is 0, optionally waits for the rollout and returns the deployed version
+func deployConfigVersion(ctx context.Context, client *APIClient, confID string, version int64, rollout *rolloutOptions) (int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
 
-	// Step 2: Conditionally deploy if auto_deploy is true
-	if autoDeploy {
+	if version == 0 {
 		// Get the latest config history version (timestamp) after save
-		version, err := client.GetLatestConfigHistoryVersion(confID)
+		latest, err := client.GetLatestConfigHistoryVersion(ctx, confID)
 		if err != nil {
 			diags = append(diags, diag.Diagnostic{
 				Severity: diag.Error,
 				Summary:  "Could not get latest config history version for deployment",
-				Detail:   fmt.Sprintf("%s", err),
+				Detail:   apiErrorDetail(err),
 			})
-			return nil, diags
+			return 0, diags
 		}
-
-		// Deploy the saved version
-		_, err = client.DeployConfig(confID, version)
+		version = latest
+	} else {
+		histories, err := client.GetConfigHistory(ctx, confID)
 		if err != nil {
 			diags = append(diags, diag.Diagnostic{
 				Severity: diag.Error,
-				Summary:  "Could not deploy the config resource",
-				Detail:   fmt.Sprintf("Config was saved but deployment failed: %s", err),
+				Summary:  "Could not get config history for deployment",
+				Detail:   apiErrorDetail(err),
 			})
-			return nil, diags
+			return 0, diags
+		}
+		if !hasConfigHistoryVersion(histories, version) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Config history version not found",
+				Detail:   fmt.Sprintf("Version %d is not in the history of config '%s'", version, confID),
+			})
+			return 0, diags
+		}
+	}
+
+	// Deploy the version
+	if _, err := client.DeployConfig(ctx, confID, version); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not deploy the config resource",
+			Detail:   fmt.Sprintf("Deployment of version %d failed: %s", version, apiErrorDetail(err)),
+		})
+		return 0, diags
+	}
+
+	// Optionally wait until the agents run the deployed version
+	if rollout != nil {
+		if rolloutDiags := waitForRollout(ctx, client, confID, version, rollout); len(rolloutDiags) > 0 {
+			diags = append(diags, rolloutDiags...)
+			return 0, diags
+		}
+	}
+
+	return version, diags
+}
+
+// hasConfigHistoryVersion reports whether version is one of the saved versions in histories
+func hasConfigHistoryVersion(histories []ConfigHistory, version int64) bool {
+	for _, h := range histories {
+		if h.Timestamp == version {
+			return true
 		}
 	}
+	return false
+}
 
-	return saveResp, diags
+// deployedConfigVersion returns the version the config is deployed with, or 0 if it was never deployed.
+// It comes from the pipeline status: the history can't tell, since several of its entries keep the
+// deployed status after a rollback to an older version.
+func deployedConfigVersion(ctx context.Context, client *APIClient, confID string) (int64, error) {
+	status, err := client.GetPipelineStatus(ctx, confID)
+	if err != nil {
+		return 0, err
+	}
+	return status.DeployedVersion, nil
+}
+
+// customizeDiffDeployedVersion marks deployed_version as unknown when the apply will deploy a
+// new version: the content changes (or auto_deploy is turned on) and no version is pinned explicitly.
+// A pinned version can't be combined with such a change, the new version would replace the pin
+// and the next plan would deploy the pin again.
+func customizeDiffDeployedVersion(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		if v, ok := d.GetOk("deployed_version"); ok && d.Get("conf_id").(string) == "" {
+			return fmt.Errorf("deployed_version can't be set when creating a new config (got %d), set it once the config exists", int64(v.(float64)))
+		}
+		return nil
+	}
+	if !d.Get("auto_deploy").(bool) || d.HasChange("deployed_version") {
+		return nil
+	}
+	if !d.HasChange("config_content") && !d.HasChange("description") && !d.HasChange("auto_deploy") {
+		return nil
+	}
+	if deployedVersionConfigured(d) {
+		return fmt.Errorf("deployed_version is pinned to %d, so changes can't be deployed with auto_deploy = true. "+
+			"Set auto_deploy = false to only save them, or remove deployed_version to deploy them", int64(d.Get("deployed_version").(float64)))
+	}
+	return d.SetNewComputed("deployed_version")
+}
+
+// deployedVersionConfigured reports whether deployed_version is set in the configuration, rather
+// than computed from the deployed version
+func deployedVersionConfigured(d *schema.ResourceDiff) bool {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() || !raw.Type().IsObjectType() || !raw.Type().HasAttribute("deployed_version") {
+		return false
+	}
+	return !raw.GetAttr("deployed_version").IsNull()
 }
 
 func resourceConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -303,6 +495,7 @@ func resourceConfigCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	if len(args.diags) > 0 {
 		return args.diags
 	}
+	args.rollout = args.rollout.within(d.Timeout(schema.TimeoutCreate))
 	confDataObj := Config{
 		Content:      args.confData,
 		Environment:  args.environment,
@@ -313,12 +506,12 @@ func resourceConfigCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 	if args.confID == "" {
 		// Create a new config
-		apiResp, err := meta.client.CreateConfig(confDataObj)
+		apiResp, err := meta.client.CreateConfig(ctx, confDataObj)
 		if err != nil {
 			args.diags = append(args.diags, diag.Diagnostic{
 				Severity: diag.Error,
 				Summary:  "Could not create the config resource",
-				Detail:   fmt.Sprintf("%s", err),
+				Detail:   apiErrorDetail(err),
 			})
 			return args.diags
 		}
@@ -327,12 +520,28 @@ func resourceConfigCreate(ctx context.Context, d *schema.ResourceData, m interfa
 		args.diags = setWithError(d, "tag", apiResp.Tag, args.diags)
 
 	} else {
-		// First run of the terraform config, save the existing ed-config
+		// First run of the terraform config, capture what the adopted config looked like so
+		// that it can be restored on destroy, then save the existing ed-config
+		if configLifecycleMode(d) == AdoptLifecycleMode {
+			original, err := meta.client.GetConfigWithID(ctx, args.confID)
+			if err != nil {
+				args.diags = append(args.diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Could not get the config to adopt",
+					Detail:   apiErrorDetail(err),
+				})
+				return args.diags
+			}
+			args.diags = setWithError(d, "original_content", original.Content, args.diags)
+			args.diags = setWithError(d, "original_description", original.Description, args.diags)
+		}
 		saveReq := SaveRequest{
 			Content:     &args.confData,
 			Description: args.description,
 		}
-		saveResp, saveDiags := saveAndDeployConfig(meta.client, args.confID, saveReq, args.autoDeploy, " (create=>save)")
+		// A pinned deployed_version is deployed even if auto_deploy is false, like in Update
+		pinnedVersion := int64(d.Get("deployed_version").(float64))
+		saveResp, deployedVersion, saveDiags := saveAndDeployConfig(ctx, meta.client, args.confID, saveReq, args.autoDeploy || pinnedVersion != 0, pinnedVersion, args.rollout, " (create=>save)")
 		if len(saveDiags) > 0 {
 			args.diags = append(args.diags, saveDiags...)
 			return args.diags
@@ -340,16 +549,81 @@ func resourceConfigCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 		d.SetId(saveResp.ID)
 		args.diags = setWithError(d, "conf_id", saveResp.ID, args.diags)
+		if deployedVersion != 0 {
+			args.diags = setWithError(d, "deployed_version", float64(deployedVersion), args.diags)
+		}
 		// Get the full config to get tag
-		configResp, err := meta.client.GetConfigWithID(args.confID)
+		configResp, err := meta.client.GetConfigWithID(ctx, args.confID)
 		if err == nil {
 			args.diags = setWithError(d, "tag", configResp.Tag, args.diags)
 		}
 	}
+	args.diags = setWithError(d, "lifecycle_mode", string(configLifecycleMode(d)), args.diags)
 
 	return args.diags
 }
 
+// configLifecycleMode returns the lifecycle_mode of the config, inferring it from conf_id
+// when it is not set: a config referenced by conf_id already existed, so it is adopted
+func configLifecycleMode(d *schema.ResourceData) LifecycleMode {
+	if mode := d.Get("lifecycle_mode").(string); mode != "" {
+		return LifecycleMode(mode)
+	}
+	if d.Get("conf_id").(string) != "" {
+		return AdoptLifecycleMode
+	}
+	return OwnLifecycleMode
+}
+
+// customizeDiffLifecycleMode infers lifecycle_mode for new configs at plan time and rejects
+// adopting a config without conf_id
+func customizeDiffLifecycleMode(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("conf_id") {
+		return nil
+	}
+	confID := d.Get("conf_id").(string)
+	mode, ok := d.GetOk("lifecycle_mode")
+	if ok && mode.(string) == string(AdoptLifecycleMode) && confID == "" {
+		return fmt.Errorf("lifecycle_mode %q requires conf_id to reference the config to adopt", AdoptLifecycleMode)
+	}
+	if d.Id() != "" || ok {
+		return nil
+	}
+	if confID != "" {
+		return d.SetNew("lifecycle_mode", string(AdoptLifecycleMode))
+	}
+	return d.SetNew("lifecycle_mode", string(OwnLifecycleMode))
+}
+
+// customizeDiffFleet enforces the fleet rules of the API at plan time: Kubernetes Edge fleets
+// need a fleet_subtype, and the fleet of a config referenced by conf_id can't be changed since
+// replacing the resource would save into the same conf_id again, whatever the lifecycle_mode
+func customizeDiffFleet(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("environment") && d.NewValueKnown("fleet_type") && d.NewValueKnown("fleet_subtype") &&
+		d.Get("environment").(string) == string(KubernetesEnvironmentType) &&
+		d.Get("fleet_type").(string) == string(EdgeFleetType) &&
+		d.Get("fleet_subtype").(string) == "" {
+		return fmt.Errorf("fleet_subtype is required when environment is %q and fleet_type is %q", KubernetesEnvironmentType, EdgeFleetType)
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+	if oldConfID, _ := d.GetChange("conf_id"); oldConfID.(string) == "" {
+		return nil
+	}
+	for _, key := range []string{"environment", "fleet_type", "fleet_subtype"} {
+		if d.HasChange(key) {
+			from, to := d.GetChange(key)
+			return fmt.Errorf("%s of config '%s' can't be changed from %q to %q: the API can't change it in place and "+
+				"the config is referenced by conf_id, so a replacement would save into the same config again. "+
+				"Change it in Edge Delta, or create a new config without conf_id",
+				key, d.Id(), from, to)
+		}
+	}
+	return nil
+}
+
 func resourceConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	meta := m.(*ProviderMetadata)
 	args := parseArgs(d)
@@ -369,18 +643,35 @@ func resourceConfigRead(ctx context.Context, d *schema.ResourceData, m interface
 
 		activeConfID = d.Id()
 	}
-	apiResp, err := meta.client.GetConfigWithID(activeConfID)
+	apiResp, err := meta.client.GetConfigWithID(ctx, activeConfID)
 	if err != nil {
+		// Check if resource was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return args.diags
+		}
 		args.diags = append(args.diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Could not get the resource data from API",
-			Detail:   fmt.Sprintf("%s", err),
+			Detail:   apiErrorDetail(err),
 		})
 		return args.diags
 	}
 	d.SetId(apiResp.ID)
 	args.diags = setWithError(d, "conf_id", args.confID, args.diags)
-	args.diags = setWithError(d, "tag", apiResp.Tag, args.diags)
+	// Refresh every attribute from the API so out-of-band edits show up in plans
+	args.diags = setConfigState(d, (*Config)(apiResp), args.diags)
+
+	deployedVersion, err := deployedConfigVersion(ctx, meta.client, apiResp.ID)
+	if err != nil {
+		args.diags = append(args.diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Could not read the deployed version of the config",
+			Detail:   apiErrorDetail(err),
+		})
+	} else {
+		args.diags = setWithError(d, "deployed_version", float64(deployedVersion), args.diags)
+	}
 
 	return args.diags
 }
@@ -391,28 +682,82 @@ func resourceConfigUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	if len(args.diags) > 0 {
 		return args.diags
 	}
+	args.rollout = args.rollout.within(d.Timeout(schema.TimeoutUpdate))
 	confID := args.confID
 	if confID == "" {
 		// Just get the config id from the tf state
 		confID = d.Id()
 	}
 
-	// Save and optionally deploy the config
-	saveReq := SaveRequest{
-		Content:     &args.confData,
-		Description: args.description,
+	// The cluster name is config metadata that a save doesn't change
+	if d.HasChange("cluster_name") {
+		_, err := meta.client.UpdateConfigWithID(ctx, confID, Config{
+			ID:           confID,
+			Content:      args.confData,
+			Description:  args.description,
+			Environment:  args.environment,
+			FleetType:    args.fleetType,
+			FleetSubtype: args.fleetSubtype,
+			ClusterName:  args.clusterName,
+		})
+		if err != nil {
+			args.diags = append(args.diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Could not update the config resource",
+				Detail:   apiErrorDetail(err),
+			})
+			return args.diags
+		}
 	}
-	_, saveDiags := saveAndDeployConfig(meta.client, confID, saveReq, args.autoDeploy, "")
-	if len(saveDiags) > 0 {
-		args.diags = append(args.diags, saveDiags...)
+
+	// Attributes like deletion_protection or wait_for_rollout only live in the Terraform
+	// state, changing them alone must not save or deploy anything
+	if !d.HasChanges("config_content", "description", "auto_deploy", "deployed_version") {
 		return args.diags
 	}
 
+	// A changed deployed_version is deployed even if auto_deploy is false, this is how
+	// a config is rolled back or forward to a specific version
+	var pinnedVersion int64
+	versionChanged := d.HasChange("deployed_version")
+	if versionChanged {
+		pinnedVersion = int64(d.Get("deployed_version").(float64))
+	}
+
+	var deployedVersion int64
+	if versionChanged && !d.HasChange("config_content") && !d.HasChange("description") {
+		// Only the version changed, there's nothing to save
+		var deployDiags diag.Diagnostics
+		deployedVersion, deployDiags = deployConfigVersion(ctx, meta.client, confID, pinnedVersion, args.rollout)
+		if len(deployDiags) > 0 {
+			args.diags = append(args.diags, deployDiags...)
+			return args.diags
+		}
+	} else {
+		// Save and optionally deploy the config
+		saveReq := SaveRequest{
+			Content:     &args.confData,
+			Description: args.description,
+		}
+		var saveDiags diag.Diagnostics
+		_, deployedVersion, saveDiags = saveAndDeployConfig(ctx, meta.client, confID, saveReq, args.autoDeploy || versionChanged, pinnedVersion, args.rollout, "")
+		if len(saveDiags) > 0 {
+			args.diags = append(args.diags, saveDiags...)
+			return args.diags
+		}
+	}
+	if deployedVersion != 0 {
+		args.diags = setWithError(d, "deployed_version", float64(deployedVersion), args.diags)
+	}
+
 	return args.diags
 }
 
 func resourceConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	meta := m.(*ProviderMetadata)
+	if diags := checkDeletionProtection(d, "edgedelta_config"); diags.HasError() {
+		return diags
+	}
 	args := parseArgs(d)
 	if len(args.diags) > 0 {
 		return args.diags
@@ -433,15 +778,52 @@ func resourceConfigDelete(ctx context.Context, d *schema.ResourceData, m interfa
 		return args.diags
 	}
 
-	err := meta.client.DeleteConfigWithID(confID)
+	if configLifecycleMode(d) == AdoptLifecycleMode {
+		// Terraform didn't create the config, so it must not delete it
+		if d.Get("restore_on_destroy").(bool) {
+			args.diags = append(args.diags, restoreAdoptedConfig(ctx, meta.client, d, confID, args)...)
+			if args.diags.HasError() {
+				return args.diags
+			}
+		}
+		d.SetId("")
+		return args.diags
+	}
+
+	err := meta.client.DeleteConfigWithID(ctx, confID)
 	if err != nil {
+		// If already deleted, just remove from state
+		if IsNotFound(err) {
+			d.SetId("")
+			return args.diags
+		}
 		args.diags = append(args.diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Could not delete the config resource",
-			Detail:   fmt.Sprintf("%s", err),
+			Detail:   apiErrorDetail(err),
 		})
 		return args.diags
 	}
 
 	return args.diags
 }
+
+// restoreAdoptedConfig saves the content and description that the config had before it was
+// adopted and deploys them if auto_deploy is true
+func restoreAdoptedConfig(ctx context.Context, client *APIClient, d *schema.ResourceData, confID string, args *configArgs) diag.Diagnostics {
+	var diags diag.Diagnostics
+	originalContent := d.Get("original_content").(string)
+	if originalContent == "" {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Nothing to restore",
+			Detail:   fmt.Sprintf("The original content of config '%s' was not captured when it was adopted, the config is left as is", confID),
+		})
+	}
+	saveReq := SaveRequest{
+		Content:     &originalContent,
+		Description: d.Get("original_description").(string),
+	}
+	_, _, saveDiags := saveAndDeployConfig(ctx, client, confID, saveReq, args.autoDeploy, 0, nil, " (destroy=>restore)")
+	return append(diags, saveDiags...)
+}
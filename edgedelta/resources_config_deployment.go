@@ -0,0 +1,176 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceConfigDeployment() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceConfigDeploymentCreate,
+		ReadContext:   resourceConfigDeploymentRead,
+		UpdateContext: resourceConfigDeploymentUpdate,
+		DeleteContext: resourceConfigDeploymentDelete,
+		Timeouts:      rolloutTimeouts(),
+		CustomizeDiff: customizeDiffConfigDeployment,
+		Description:   "Deploys a saved version of an EdgeDelta config.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"conf_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+				Description:  "ID of the config to deploy.",
+			},
+
+			// Optional
+			// Versions are millisecond timestamps, TypeInt would overflow on 32-bit platforms
+			"version": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validateConfigVersion(0),
+				Description:  "Config history version (timestamp) to deploy. When not set, the latest saved version is deployed.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that cause a redeploy when they change, e.g. a hash of the config content.",
+			},
+			"wait_for_rollout": rolloutSchema(),
+
+			// Computed
+			"deployed_version": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Config history version (timestamp) that is currently deployed.",
+			},
+			"latest_version": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Latest saved config history version (timestamp), deployed or not.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if !validateUUID(d.Id()) {
+					return nil, fmt.Errorf("failed to validate the config ID: '%s'", d.Id())
+				}
+				if err := d.Set("conf_id", d.Id()); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+	}
+}
+
+// customizeDiffConfigDeployment plans a redeploy when a pinned version is no longer the deployed
+// one, e.g. because another version was deployed outside Terraform
+func customizeDiffConfigDeployment(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || d.HasChange("version") {
+		return nil
+	}
+	version := int64(d.Get("version").(float64))
+	deployed := int64(d.Get("deployed_version").(float64))
+	if version != 0 && version != deployed {
+		return d.SetNewComputed("deployed_version")
+	}
+	return nil
+}
+
+// deployConfigDeployment deploys the version of the resource, timeoutKey is the operation
+// (schema.TimeoutCreate or schema.TimeoutUpdate) whose timeout bounds the rollout wait
+func deployConfigDeployment(ctx context.Context, d *schema.ResourceData, m interface{}, timeoutKey string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	meta := m.(*ProviderMetadata)
+	confID := d.Get("conf_id").(string)
+
+	rollout, err := parseRolloutOptions(d.Get("wait_for_rollout"))
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid wait_for_rollout",
+			Detail:   err.Error(),
+		})
+	}
+
+	deployedVersion, deployDiags := deployConfigVersion(ctx, meta.client, confID, int64(d.Get("version").(float64)), rollout.within(d.Timeout(timeoutKey)))
+	if len(deployDiags) > 0 {
+		return append(diags, deployDiags...)
+	}
+	d.SetId(confID)
+	diags = setWithError(d, "deployed_version", float64(deployedVersion), diags)
+	return diags
+}
+
+func resourceConfigDeploymentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	diags := deployConfigDeployment(ctx, d, m, schema.TimeoutCreate)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceConfigDeploymentRead(ctx, d, m)...)
+}
+
+func resourceConfigDeploymentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	meta := m.(*ProviderMetadata)
+
+	histories, err := meta.client.GetConfigHistory(ctx, d.Id())
+	if err != nil {
+		// Check if the config was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not get the config history",
+			Detail:   apiErrorDetail(err),
+		})
+	}
+
+	deployedVersion, err := deployedConfigVersion(ctx, meta.client, d.Id())
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not get the deployed version of the config",
+			Detail:   apiErrorDetail(err),
+		})
+	}
+
+	var latest int64
+	for _, h := range histories {
+		if h.Timestamp > latest {
+			latest = h.Timestamp
+		}
+	}
+	diags = setWithError(d, "conf_id", d.Id(), diags)
+	diags = setWithError(d, "deployed_version", float64(deployedVersion), diags)
+	diags = setWithError(d, "latest_version", float64(latest), diags)
+	return diags
+}
+
+func resourceConfigDeploymentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Changing only wait_for_rollout doesn't deploy anything
+	if !d.HasChanges("version", "triggers", "deployed_version") {
+		return nil
+	}
+	diags := deployConfigDeployment(ctx, d, m, schema.TimeoutUpdate)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceConfigDeploymentRead(ctx, d, m)...)
+}
+
+func resourceConfigDeploymentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// A deployment can't be undone, the config keeps running the deployed version.
+	// Only remove the resource from the state.
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,134 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceConfigDeploymentCreate(t *testing.T) {
+	deployed := int64(1718000003000)
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/history"):
+			_, _ = w.Write([]byte(`[
+				{"timestamp": 1718000003000, "status": "deployed"},
+				{"timestamp": 1718000002000, "status": "deployed"}
+			]`))
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			_, _ = fmt.Fprintf(w, `{"config_id": %q, "deployed_version": %d}`, testConfigID, deployed)
+		case strings.HasSuffix(r.URL.Path, "/deploy/1718000002000"):
+			deployed = 1718000002000
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceConfigDeployment().Schema, map[string]interface{}{
+		"conf_id": testConfigID,
+		"version": float64(1718000002000),
+	})
+
+	diags := resourceConfigDeploymentCreate(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != testConfigID {
+		t.Errorf("expected ID %q, got %q", testConfigID, d.Id())
+	}
+	if got := int64(d.Get("deployed_version").(float64)); got != 1718000002000 {
+		t.Errorf("expected deployed_version 1718000002000, got %d", got)
+	}
+	if got := int64(d.Get("latest_version").(float64)); got != 1718000003000 {
+		t.Errorf("expected latest_version 1718000003000, got %d", got)
+	}
+}
+
+func TestResourceConfigDeploymentPlan_RolledBack(t *testing.T) {
+	// Both versions keep the deployed status in the history, the older one is live
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/history"):
+			_, _ = w.Write([]byte(`[
+				{"timestamp": 1718000003000, "status": "deployed"},
+				{"timestamp": 1718000002000, "status": "deployed"}
+			]`))
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			_, _ = w.Write([]byte(`{"config_id": "` + testConfigID + `", "deployed_version": 1718000002000}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	meta := newTestProviderMetadata(server.URL)
+
+	raw := map[string]interface{}{
+		"conf_id": testConfigID,
+		"version": float64(1718000002000),
+	}
+	d := schema.TestResourceDataRaw(t, resourceConfigDeployment().Schema, raw)
+	d.SetId(testConfigID)
+	if diags := resourceConfigDeploymentRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got := int64(d.Get("deployed_version").(float64)); got != 1718000002000 {
+		t.Fatalf("expected deployed_version 1718000002000, got %d", got)
+	}
+
+	diff, err := resourceConfigDeployment().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("expected an empty plan for the deployed pinned version, got %v", diff.Attributes)
+	}
+}
+
+func TestResourceConfigDeploymentRead_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceConfigDeployment().Schema, map[string]interface{}{
+		"conf_id": testConfigID,
+	})
+	d.SetId(testConfigID)
+
+	diags := resourceConfigDeploymentRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the deployment to be removed from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceConfigDeploymentDelete(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no API request on delete, got %s %s", r.Method, r.URL.Path)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceConfigDeployment().Schema, map[string]interface{}{
+		"conf_id": testConfigID,
+	})
+	d.SetId(testConfigID)
+
+	diags := resourceConfigDeploymentDelete(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the deployment to be removed from state, got ID %q", d.Id())
+	}
+}
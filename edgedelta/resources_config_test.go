@@ -0,0 +1,609 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newTestProviderMetadata returns provider metadata whose client points to the mock server
+func newTestProviderMetadata(serverURL string) *ProviderMetadata {
+	return &ProviderMetadata{client: newTestClient(serverURL)}
+}
+
+func TestResourceConfigRead_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "config not found"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceConfig().Schema, map[string]interface{}{
+		"config_content": "a: b",
+		"environment":    "Linux",
+	})
+	d.SetId(testConfigID)
+
+	diags := resourceConfigRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the config to be removed from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceConfigRead_Refresh(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		// Rolled back to 1000, which the history can't tell apart from 2000
+		if strings.HasSuffix(r.URL.Path, "/history") {
+			_, _ = w.Write([]byte(`[
+				{"timestamp": 3000, "status": "saved"},
+				{"timestamp": 2000, "status": "deployed"},
+				{"timestamp": 1000, "status": "deployed"}
+			]`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/status") {
+			_, _ = w.Write([]byte(`{"config_id": "` + testConfigID + `", "deployed_version": 1000}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"id": "` + testConfigID + `",
+			"content": "version: v3\nsettings:\n  tag: edited-in-ui\nnodes: []\n",
+			"tag": "edited-in-ui",
+			"environment": "Kubernetes",
+			"fleet_type": "Edge",
+			"fleet_subtype": "Gateway",
+			"cluster_name": "prod-cluster",
+			"description": "edited"
+		}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceConfig().Schema, map[string]interface{}{
+		"config_content": "version: v3\nsettings:\n  tag: prod\nnodes: []\n",
+		"environment":    "Linux",
+	})
+	d.SetId(testConfigID)
+
+	diags := resourceConfigRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	expected := map[string]string{
+		"config_content": "version: v3\nsettings:\n  tag: edited-in-ui\nnodes: []\n",
+		"tag":            "edited-in-ui",
+		"environment":    "Kubernetes",
+		"fleet_type":     "Edge",
+		"fleet_subtype":  "Gateway",
+		"cluster_name":   "prod-cluster",
+		"description":    "edited",
+	}
+	for key, want := range expected {
+		if got := d.Get(key).(string); got != want {
+			t.Errorf("expected %s to be %q, got %q", key, want, got)
+		}
+	}
+	if got := int64(d.Get("deployed_version").(float64)); got != 1000 {
+		t.Errorf("expected deployed_version to be the live version 1000, got %d", got)
+	}
+}
+
+func TestDeployConfigVersion_Pinned(t *testing.T) {
+	var deployPath string
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/history"):
+			_, _ = w.Write([]byte(`[{"timestamp": 3000, "status": "deployed"}, {"timestamp": 1000, "status": "deployed"}]`))
+		case strings.Contains(r.URL.Path, "/deploy/"):
+			deployPath = r.URL.Path
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	version, diags := deployConfigVersion(context.Background(), client, testConfigID, 1000, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if version != 1000 {
+		t.Errorf("expected version 1000 to be deployed, got %d", version)
+	}
+	if !strings.HasSuffix(deployPath, "/"+testConfigID+"/deploy/1000") {
+		t.Errorf("unexpected deploy path: %s", deployPath)
+	}
+}
+
+func TestDeployConfigVersion_UnknownVersion(t *testing.T) {
+	deployed := false
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/deploy/") {
+			deployed = true
+		}
+		_, _ = w.Write([]byte(`[{"timestamp": 3000, "status": "deployed"}]`))
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	_, diags := deployConfigVersion(context.Background(), client, testConfigID, 1234, nil)
+	if !diags.HasError() {
+		t.Fatal("expected an error for a version that is not in the history")
+	}
+	if deployed {
+		t.Error("expected no deploy request for an unknown version")
+	}
+}
+
+func TestResourceConfigDelete_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         map[string]interface{}
+		wantDelete  bool
+		wantRestore bool
+	}{
+		{
+			name:       "own",
+			raw:        map[string]interface{}{"lifecycle_mode": "own"},
+			wantDelete: true,
+		},
+		{
+			name: "adopt",
+			raw:  map[string]interface{}{"conf_id": testConfigID, "lifecycle_mode": "adopt"},
+		},
+		{
+			name: "adopt inferred from conf_id",
+			raw:  map[string]interface{}{"conf_id": testConfigID},
+		},
+		{
+			name:       "own with conf_id",
+			raw:        map[string]interface{}{"conf_id": testConfigID, "lifecycle_mode": "own"},
+			wantDelete: true,
+		},
+		{
+			name:        "adopt and restore",
+			raw:         map[string]interface{}{"conf_id": testConfigID, "lifecycle_mode": "adopt", "restore_on_destroy": true, "auto_deploy": false},
+			wantRestore: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted bool
+			var restored *SaveRequest
+			server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodDelete:
+					deleted = true
+				case strings.HasSuffix(r.URL.Path, "/save"):
+					restored = &SaveRequest{}
+					if err := json.NewDecoder(r.Body).Decode(restored); err != nil {
+						t.Errorf("failed to decode the save request: %v", err)
+					}
+					_, _ = w.Write([]byte(`{"id": "` + testConfigID + `"}`))
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			})
+			defer server.Close()
+
+			raw := map[string]interface{}{
+				"config_content": "a: b",
+				"environment":    "Linux",
+			}
+			for k, v := range tt.raw {
+				raw[k] = v
+			}
+			d := schema.TestResourceDataRaw(t, resourceConfig().Schema, raw)
+			d.SetId(testConfigID)
+			if err := d.Set("original_content", "original: true"); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.Set("original_description", "before terraform"); err != nil {
+				t.Fatal(err)
+			}
+
+			diags := resourceConfigDelete(context.Background(), d, newTestProviderMetadata(server.URL))
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if deleted != tt.wantDelete {
+				t.Errorf("expected delete request: %t, got: %t", tt.wantDelete, deleted)
+			}
+			if tt.wantRestore {
+				if restored == nil {
+					t.Fatal("expected the original content to be saved")
+				}
+				if restored.Content == nil || *restored.Content != "original: true" || restored.Description != "before terraform" {
+					t.Errorf("unexpected restore request: %+v", restored)
+				}
+			} else if restored != nil {
+				t.Errorf("expected no save request, got %+v", restored)
+			}
+		})
+	}
+}
+
+func TestResourceConfigCreate_AdoptCapturesOriginal(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/save"):
+			_, _ = w.Write([]byte(`{"id": "` + testConfigID + `"}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/confs/"+testConfigID):
+			_, _ = w.Write([]byte(`{"id": "` + testConfigID + `", "content": "original: true", "description": "before terraform", "tag": "prod"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceConfig().Schema, map[string]interface{}{
+		"conf_id":        testConfigID,
+		"config_content": "a: b",
+		"environment":    "Linux",
+		"auto_deploy":    false,
+	})
+
+	diags := resourceConfigCreate(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	expected := map[string]string{
+		"lifecycle_mode":       "adopt",
+		"original_content":     "original: true",
+		"original_description": "before terraform",
+	}
+	for key, want := range expected {
+		if got := d.Get(key).(string); got != want {
+			t.Errorf("expected %s to be %q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestResourceConfigCreate_PinnedVersion(t *testing.T) {
+	var deployPath string
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/save"):
+			_, _ = w.Write([]byte(`{"id": "` + testConfigID + `"}`))
+		case strings.HasSuffix(r.URL.Path, "/history"):
+			_, _ = w.Write([]byte(`[{"timestamp": 1718000000001, "status": "saved"}, {"timestamp": 1718000000000, "status": "deployed"}]`))
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			_, _ = w.Write([]byte(`{"config_id": "` + testConfigID + `", "deployed_version": 1718000000000}`))
+		case strings.Contains(r.URL.Path, "/deploy/"):
+			deployPath = r.URL.Path
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/confs/"+testConfigID):
+			_, _ = w.Write([]byte(`{"id": "` + testConfigID + `", "content": "a: b", "tag": "prod"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceConfig().Schema, map[string]interface{}{
+		"conf_id":          testConfigID,
+		"config_content":   "a: b",
+		"environment":      "Linux",
+		"auto_deploy":      false,
+		"deployed_version": float64(1718000000000),
+	})
+
+	diags := resourceConfigCreate(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if !strings.HasSuffix(deployPath, "/"+testConfigID+"/deploy/1718000000000") {
+		t.Errorf("expected the pinned version to be deployed even with auto_deploy = false, got deploy path %q", deployPath)
+	}
+	if got := int64(d.Get("deployed_version").(float64)); got != 1718000000000 {
+		t.Errorf("expected deployed_version to be 1718000000000, got %d", got)
+	}
+}
+
+func TestResourceConfigDelete_DeletionProtection(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no API request, got %s %s", r.Method, r.URL.Path)
+	})
+	defer server.Close()
+
+	// Deletion protection is checked before the lifecycle mode, so an adopted config
+	// with restore_on_destroy isn't restored either
+	d := schema.TestResourceDataRaw(t, resourceConfig().Schema, map[string]interface{}{
+		"conf_id":             testConfigID,
+		"config_content":      "a: b",
+		"environment":         "Linux",
+		"restore_on_destroy":  true,
+		"deletion_protection": true,
+	})
+	d.SetId(testConfigID)
+
+	diags := resourceConfigDelete(context.Background(), d, newTestProviderMetadata(server.URL))
+	if !diags.HasError() {
+		t.Fatal("expected an error for a protected config")
+	}
+	if d.Id() != testConfigID {
+		t.Errorf("expected the config to stay in state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceConfigDiff_Fleet(t *testing.T) {
+	existing := &terraform.InstanceState{
+		ID: testConfigID,
+		Attributes: map[string]string{
+			"id":             testConfigID,
+			"conf_id":        testConfigID,
+			"config_content": "a: b",
+			"environment":    "Linux",
+			"fleet_type":     "Edge",
+			"auto_deploy":    "true",
+		},
+	}
+	created := &terraform.InstanceState{
+		ID: testConfigID,
+		Attributes: map[string]string{
+			"id":             testConfigID,
+			"config_content": "a: b",
+			"environment":    "Linux",
+			"fleet_type":     "Edge",
+			"auto_deploy":    "true",
+			"lifecycle_mode": "own",
+		},
+	}
+	tests := []struct {
+		name        string
+		state       *terraform.InstanceState
+		raw         map[string]interface{}
+		wantErr     string
+		wantReplace bool
+	}{
+		{
+			name:    "kubernetes edge without subtype",
+			raw:     map[string]interface{}{"config_content": "a: b", "environment": "Kubernetes"},
+			wantErr: "fleet_subtype is required",
+		},
+		{
+			name: "kubernetes edge with subtype",
+			raw:  map[string]interface{}{"config_content": "a: b", "environment": "Kubernetes", "fleet_subtype": "Gateway"},
+		},
+		{
+			name: "kubernetes cloud without subtype",
+			raw:  map[string]interface{}{"config_content": "a: b", "environment": "Kubernetes", "fleet_type": "Cloud"},
+		},
+		{
+			name:    "adopted config environment change",
+			state:   existing,
+			raw:     map[string]interface{}{"conf_id": testConfigID, "config_content": "a: b", "environment": "Windows"},
+			wantErr: "can't be changed",
+		},
+		{
+			name:    "owned config environment change",
+			state:   existing,
+			raw:     map[string]interface{}{"conf_id": testConfigID, "lifecycle_mode": "own", "config_content": "a: b", "environment": "Windows"},
+			wantErr: "can't be changed",
+		},
+		{
+			name:        "created config environment change",
+			state:       created,
+			raw:         map[string]interface{}{"config_content": "a: b", "environment": "Windows"},
+			wantReplace: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := resourceConfig().Diff(context.Background(), tt.state, terraform.NewResourceConfigRaw(tt.raw), nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.state == nil {
+				return
+			}
+			if got := diff != nil && diff.RequiresNew(); got != tt.wantReplace {
+				t.Errorf("expected replacement: %t, got: %t", tt.wantReplace, got)
+			}
+		})
+	}
+}
+
+const testOtherConfigID = "00000000-0000-0000-0000-000000000002"
+
+func TestResourceConfigImport(t *testing.T) {
+	configs := map[string]string{
+		testConfigID: `{
+			"id": "` + testConfigID + `",
+			"content": "a: b",
+			"tag": "prod",
+			"environment": "Kubernetes",
+			"fleet_type": "Edge",
+			"fleet_subtype": "Gateway",
+			"cluster_name": "prod-cluster",
+			"description": "production"
+		}`,
+		testOtherConfigID: `{
+			"id": "` + testOtherConfigID + `",
+			"content": "c: d",
+			"tag": "dev",
+			"environment": "Linux",
+			"fleet_type": "Cloud"
+		}`,
+	}
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/orgs/"+testOrgID+"/confs" {
+			_, _ = w.Write([]byte(`[` + configs[testConfigID] + `,` + configs[testOtherConfigID] + `]`))
+			return
+		}
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		body, ok := configs[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	})
+	defer server.Close()
+
+	expected := map[string]map[string]interface{}{
+		testConfigID: {
+			"conf_id":             testConfigID,
+			"config_content":      "a: b",
+			"tag":                 "prod",
+			"environment":         "Kubernetes",
+			"fleet_type":          "Edge",
+			"fleet_subtype":       "Gateway",
+			"cluster_name":        "prod-cluster",
+			"description":         "production",
+			"lifecycle_mode":      "adopt",
+			"original_content":    "a: b",
+			"auto_deploy":         true,
+			"deletion_protection": false,
+		},
+		testOtherConfigID: {
+			"conf_id":        testOtherConfigID,
+			"config_content": "c: d",
+			"tag":            "dev",
+			"environment":    "Linux",
+			"fleet_type":     "Cloud",
+			"fleet_subtype":  "",
+			"lifecycle_mode": "adopt",
+			"auto_deploy":    true,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		wantIDs []string
+	}{
+		{name: "single", id: testConfigID, wantIDs: []string{testConfigID}},
+		{name: "comma-separated with whitespace", id: " " + testConfigID + " , " + testOtherConfigID + ", ", wantIDs: []string{testConfigID, testOtherConfigID}},
+		{name: "all", id: "*", wantIDs: []string{testConfigID, testOtherConfigID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := resourceConfig().Data(nil)
+			d.SetId(tt.id)
+			results, err := resourceConfigImport(context.Background(), d, newTestProviderMetadata(server.URL))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != len(tt.wantIDs) {
+				t.Fatalf("expected %d imported configs, got %d", len(tt.wantIDs), len(results))
+			}
+			for i, dd := range results {
+				if dd.Id() != tt.wantIDs[i] {
+					t.Fatalf("results[%d]: expected ID %q, got %q", i, tt.wantIDs[i], dd.Id())
+				}
+				for key, want := range expected[dd.Id()] {
+					if got := dd.Get(key); got != want {
+						t.Errorf("%s: expected %s to be %v, got %v", dd.Id(), key, want, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestResourceConfigImport_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := resourceConfig().Data(nil)
+	d.SetId(testConfigID)
+	if _, err := resourceConfigImport(context.Background(), d, newTestProviderMetadata(server.URL)); err == nil {
+		t.Fatal("expected an error for a missing config")
+	}
+}
+
+func TestResourceConfigDiff_PinnedVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		raw          map[string]interface{}
+		wantErr      string
+		wantComputed bool
+	}{
+		{
+			name:         "content change without a pin",
+			raw:          map[string]interface{}{"conf_id": testConfigID, "config_content": "a: c", "environment": "Linux"},
+			wantComputed: true,
+		},
+		{
+			name:    "content change with the same pin",
+			raw:     map[string]interface{}{"conf_id": testConfigID, "config_content": "a: c", "environment": "Linux", "deployed_version": float64(1718000000000)},
+			wantErr: "deployed_version is pinned to 1718000000000",
+		},
+		{
+			name: "content change with the pin and auto_deploy off",
+			raw:  map[string]interface{}{"conf_id": testConfigID, "config_content": "a: c", "environment": "Linux", "auto_deploy": false, "deployed_version": float64(1718000000000)},
+		},
+		{
+			name: "no change with the pin",
+			raw:  map[string]interface{}{"conf_id": testConfigID, "config_content": "a: b", "environment": "Linux", "deployed_version": float64(1718000000000)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(tt.raw)
+			state := &terraform.InstanceState{
+				ID: testConfigID,
+				Attributes: map[string]string{
+					"id":               testConfigID,
+					"conf_id":          testConfigID,
+					"config_content":   "a: b",
+					"environment":      "Linux",
+					"fleet_type":       "Edge",
+					"auto_deploy":      "true",
+					"deployed_version": "1718000000000",
+				},
+				// Terraform sends the configuration along with the prior state
+				RawConfig: rawConfigValue(tt.raw),
+			}
+
+			diff, err := resourceConfig().Diff(context.Background(), state, config, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			attr, ok := diff.GetAttribute("deployed_version")
+			if got := ok && attr.NewComputed; got != tt.wantComputed {
+				t.Errorf("expected deployed_version to be computed: %t, got: %t", tt.wantComputed, got)
+			}
+		})
+	}
+}
+
+// rawConfigValue converts a flat test configuration into the value Terraform sends as the raw config
+func rawConfigValue(raw map[string]interface{}) cty.Value {
+	attrs := make(map[string]cty.Value, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			attrs[k] = cty.StringVal(v)
+		case bool:
+			attrs[k] = cty.BoolVal(v)
+		case float64:
+			attrs[k] = cty.NumberFloatVal(v)
+		}
+	}
+	return cty.ObjectVal(attrs)
+}
@@ -11,11 +11,24 @@ import (
 
 func resourceDashboard() *schema.Resource {
 	return &schema.Resource{
-		SchemaVersion: 1,
+		SchemaVersion: 3,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 1,
+				Type:    resourceDashboardV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceDashboardStateUpgradeV1,
+			},
+			{
+				Version: 2,
+				Type:    resourceDashboardV2().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceDashboardStateUpgradeV2,
+			},
+		},
 		CreateContext: resourceDashboardCreate,
 		ReadContext:   resourceDashboardRead,
 		UpdateContext: resourceDashboardUpdate,
 		DeleteContext: resourceDashboardDelete,
+		CustomizeDiff: customizeDiffDashboardAccess,
 		Description:   "Manages an EdgeDelta dashboard resource.",
 		Schema: map[string]*schema.Schema{
 			// Required
@@ -37,6 +50,7 @@ func resourceDashboard() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "Searchable tags for the dashboard.",
 			},
+			"deletion_protection": deletionProtectionSchema(),
 			"definition": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -44,6 +58,34 @@ func resourceDashboard() *schema.Resource {
 				ValidateFunc:     validateJSON,
 				Description:      "Full dashboard definition as a JSON string. Can include 'definition', 'resource_accesses', and 'sharing_security_settings' fields. Use file() to load from a JSON file.",
 			},
+			"sharing": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Sharing settings of the dashboard. Replaces 'sharing_security_settings' in definition.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"public": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Allow anyone with the link to view the dashboard without logging in.",
+						},
+						"allowed_domains": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Email domains the dashboard can be shared with, e.g. \"example.com\". Any domain if empty.",
+						},
+					},
+				},
+			},
+			"manage_resource_accesses": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Manage the 'resource_accesses' of the definition. Set it to false when the accesses are managed with edgedelta_dashboard_access resources.",
+			},
 
 			// Computed
 			"dashboard_id": {
@@ -82,7 +124,7 @@ func resourceDashboard() *schema.Resource {
 
 				// Support importing all dashboards with "*"
 				if dashboardID == "*" {
-					dashboards, err := meta.client.GetAllDashboards()
+					dashboards, err := meta.client.GetAllDashboards(ctx)
 					if err != nil {
 						return nil, fmt.Errorf("could not get dashboards from API: %s", err)
 					}
@@ -90,6 +132,9 @@ func resourceDashboard() *schema.Resource {
 					for _, dash := range dashboards {
 						dd := resourceDashboard().Data(nil)
 						dd.SetId(dash.DashboardID)
+						if err := setDashboardDefaults(dd); err != nil {
+							return nil, fmt.Errorf("failed to set dashboard state: %s", err)
+						}
 						if err := setDashboardState(dd, dash); err != nil {
 							return nil, fmt.Errorf("failed to set dashboard state: %s", err)
 						}
@@ -110,11 +155,14 @@ func resourceDashboard() *schema.Resource {
 				for _, id := range dashboardIDs {
 					id = strings.TrimSpace(id)
 					dd := resourceDashboard().Data(nil)
-					resp, err := meta.client.GetDashboard(id)
+					resp, err := meta.client.GetDashboard(ctx, id)
 					if err != nil {
 						return nil, fmt.Errorf("could not get dashboard from API: %s (dashboard ID was: '%s')", err, id)
 					}
 					dd.SetId(resp.DashboardID)
+					if err := setDashboardDefaults(dd); err != nil {
+						return nil, fmt.Errorf("failed to set dashboard state: %s", err)
+					}
 					dashboard := Dashboard(*resp)
 					if err := setDashboardState(dd, &dashboard); err != nil {
 						return nil, fmt.Errorf("failed to set dashboard state: %s", err)
@@ -134,11 +182,80 @@ type dashboardArgs struct {
 	definition              map[string]interface{}
 	resourceAccesses        []map[string]interface{}
 	sharingSecuritySettings map[string]interface{}
+	manageResourceAccesses  bool
 	diags                   diag.Diagnostics
 }
 
+// Keys of the sharing_security_settings of a dashboard
+const (
+	dashboardSharingPublicKey         = "public"
+	dashboardSharingAllowedDomainsKey = "allowed_domains"
+)
+
+// setDashboardDefaults sets the defaults of the state-only attributes, imported resources
+// have no configuration to take them from
+func setDashboardDefaults(d *schema.ResourceData) error {
+	if err := d.Set("deletion_protection", false); err != nil {
+		return err
+	}
+	return d.Set("manage_resource_accesses", true)
+}
+
+// customizeDiffDashboardAccess rejects definitions with fields that are managed elsewhere
+func customizeDiffDashboardAccess(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("definition") {
+		return nil
+	}
+	defStr := d.Get("definition").(string)
+	if defStr == "" {
+		return nil
+	}
+	fullDef, err := stringToJSONMap(defStr)
+	if err != nil {
+		// Reported by validateJSON
+		return nil
+	}
+	if _, ok := fullDef["resource_accesses"]; ok && !d.Get("manage_resource_accesses").(bool) {
+		return fmt.Errorf("definition must not contain 'resource_accesses' when manage_resource_accesses is false, " +
+			"they are managed with edgedelta_dashboard_access resources")
+	}
+	if _, ok := fullDef["sharing_security_settings"]; ok && len(d.Get("sharing").([]interface{})) > 0 {
+		return fmt.Errorf("definition must not contain 'sharing_security_settings' when the sharing block is set")
+	}
+	return nil
+}
+
+// dashboardSharingSettings converts the sharing block into sharing_security_settings
+func dashboardSharingSettings(raw map[string]interface{}) map[string]interface{} {
+	settings := map[string]interface{}{
+		dashboardSharingPublicKey:         false,
+		dashboardSharingAllowedDomainsKey: []interface{}{},
+	}
+	// An empty sharing {} block is read back as a nil element
+	if raw != nil {
+		settings[dashboardSharingPublicKey] = raw["public"].(bool)
+		settings[dashboardSharingAllowedDomainsKey] = raw["allowed_domains"].([]interface{})
+	}
+	return settings
+}
+
+// dashboardSharingBlock converts sharing_security_settings into the sharing block
+func dashboardSharingBlock(settings map[string]interface{}) []interface{} {
+	public, _ := settings[dashboardSharingPublicKey].(bool)
+	domains, _ := settings[dashboardSharingAllowedDomainsKey].([]interface{})
+	if domains == nil {
+		domains = []interface{}{}
+	}
+	return []interface{}{map[string]interface{}{
+		"public":          public,
+		"allowed_domains": domains,
+	}}
+}
+
 func parseDashboardArgs(d *schema.ResourceData) *dashboardArgs {
-	args := &dashboardArgs{}
+	args := &dashboardArgs{
+		manageResourceAccesses: d.Get("manage_resource_accesses").(bool),
+	}
 
 	// Required field
 	if v, ok := d.GetOk("dashboard_name"); ok {
@@ -196,9 +313,51 @@ func parseDashboardArgs(d *schema.ResourceData) *dashboardArgs {
 		}
 	}
 
+	if v, ok := d.GetOk("sharing"); ok {
+		raw, _ := v.([]interface{})[0].(map[string]interface{})
+		args.sharingSecuritySettings = dashboardSharingSettings(raw)
+	}
+	if !args.manageResourceAccesses {
+		args.resourceAccesses = nil
+	}
+
 	return args
 }
 
+// DashboardDefinition combines the definition, and optionally the resource accesses and sharing
+// settings, of a dashboard into the document that edgedelta_dashboard.definition holds.
+// It returns nil if all of them are empty.
+func DashboardDefinition(dash *Dashboard, withAccesses, withSharing bool) map[string]interface{} {
+	combined := make(map[string]interface{})
+	if dash.Definition != nil {
+		combined["definition"] = dash.Definition
+	}
+	if withAccesses && dash.ResourceAccesses != nil {
+		combined["resource_accesses"] = dash.ResourceAccesses
+	}
+	if withSharing && dash.SharingSecuritySettings != nil {
+		combined["sharing_security_settings"] = dash.SharingSecuritySettings
+	}
+	if len(combined) == 0 {
+		return nil
+	}
+	return combined
+}
+
+// definitionHasField reports whether the definition JSON sets the top-level field. An empty
+// definition, as when importing, is taken to set every field.
+func definitionHasField(defStr, field string) bool {
+	if defStr == "" {
+		return true
+	}
+	fullDef, err := stringToJSONMap(defStr)
+	if err != nil {
+		return true
+	}
+	_, ok := fullDef[field]
+	return ok
+}
+
 func setDashboardState(d *schema.ResourceData, dash *Dashboard) error {
 	if err := d.Set("dashboard_id", dash.DashboardID); err != nil {
 		return err
@@ -215,18 +374,17 @@ func setDashboardState(d *schema.ResourceData, dash *Dashboard) error {
 		}
 	}
 
-	// Combine definition, resource_accesses, and sharing_security_settings into a single JSON
-	if dash.Definition != nil || dash.ResourceAccesses != nil || dash.SharingSecuritySettings != nil {
-		combinedDef := make(map[string]interface{})
-		if dash.Definition != nil {
-			combinedDef["definition"] = dash.Definition
-		}
-		if dash.ResourceAccesses != nil {
-			combinedDef["resource_accesses"] = dash.ResourceAccesses
-		}
-		if dash.SharingSecuritySettings != nil {
-			combinedDef["sharing_security_settings"] = dash.SharingSecuritySettings
+	// Fields managed by the sharing block or edgedelta_dashboard_access resources are left out
+	// of the definition, so that they don't show as drift. So are the sharing settings when the
+	// configured definition doesn't set them, e.g. after the sharing block was removed.
+	withSharing := definitionHasField(d.Get("definition").(string), "sharing_security_settings")
+	if len(d.Get("sharing").([]interface{})) > 0 {
+		if err := d.Set("sharing", dashboardSharingBlock(dash.SharingSecuritySettings)); err != nil {
+			return err
 		}
+		withSharing = false
+	}
+	if combinedDef := DashboardDefinition(dash, d.Get("manage_resource_accesses").(bool), withSharing); combinedDef != nil {
 		defStr, err := jsonMapToString(combinedDef)
 		if err != nil {
 			return err
@@ -267,12 +425,12 @@ func resourceDashboardCreate(ctx context.Context, d *schema.ResourceData, m inte
 		SharingSecuritySettings: args.sharingSecuritySettings,
 	}
 
-	resp, err := meta.client.CreateDashboard(dashboard)
+	resp, err := meta.client.CreateDashboard(ctx, dashboard)
 	if err != nil {
 		args.diags = append(args.diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Could not create the dashboard resource",
-			Detail:   err.Error(),
+			Detail:   apiErrorDetail(err),
 		})
 		return args.diags
 	}
@@ -304,17 +462,17 @@ func resourceDashboardRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diags
 	}
 
-	resp, err := meta.client.GetDashboard(dashboardID)
+	resp, err := meta.client.GetDashboard(ctx, dashboardID)
 	if err != nil {
 		// Check if resource was deleted outside Terraform
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
+		if IsNotFound(err) {
 			d.SetId("")
 			return diags
 		}
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Could not read the dashboard resource",
-			Detail:   err.Error(),
+			Detail:   apiErrorDetail(err),
 		})
 		return diags
 	}
@@ -357,12 +515,29 @@ func resourceDashboardUpdate(ctx context.Context, d *schema.ResourceData, m inte
 		SharingSecuritySettings: args.sharingSecuritySettings,
 	}
 
-	resp, err := meta.client.UpdateDashboard(dashboardID, dashboard)
+	// The update replaces the whole dashboard, so accesses managed by edgedelta_dashboard_access
+	// resources are carried over, under the same lock those resources take
+	if !args.manageResourceAccesses {
+		unlock := dashboardLocks.lock(dashboardID)
+		defer unlock()
+		current, err := meta.client.GetDashboard(ctx, dashboardID)
+		if err != nil {
+			args.diags = append(args.diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Could not read the resource accesses of the dashboard",
+				Detail:   apiErrorDetail(err),
+			})
+			return args.diags
+		}
+		dashboard.ResourceAccesses = current.ResourceAccesses
+	}
+
+	resp, err := meta.client.UpdateDashboard(ctx, dashboardID, dashboard)
 	if err != nil {
 		args.diags = append(args.diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Could not update the dashboard resource",
-			Detail:   err.Error(),
+			Detail:   apiErrorDetail(err),
 		})
 		return args.diags
 	}
@@ -382,6 +557,9 @@ func resourceDashboardUpdate(ctx context.Context, d *schema.ResourceData, m inte
 func resourceDashboardDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	meta := m.(*ProviderMetadata)
 	var diags diag.Diagnostics
+	if diags := checkDeletionProtection(d, "edgedelta_dashboard"); diags.HasError() {
+		return diags
+	}
 
 	dashboardID := d.Id()
 	if dashboardID == "" {
@@ -393,17 +571,17 @@ func resourceDashboardDelete(ctx context.Context, d *schema.ResourceData, m inte
 		return diags
 	}
 
-	err := meta.client.DeleteDashboard(dashboardID)
+	err := meta.client.DeleteDashboard(ctx, dashboardID)
 	if err != nil {
 		// If already deleted, just remove from state
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
+		if IsNotFound(err) {
 			d.SetId("")
 			return diags
 		}
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Could not delete the dashboard resource",
-			Detail:   err.Error(),
+			Detail:   apiErrorDetail(err),
 		})
 		return diags
 	}
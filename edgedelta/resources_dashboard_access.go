@@ -0,0 +1,272 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Keys of the resource_accesses entries of a dashboard
+const (
+	dashboardAccessPrincipalTypeKey = "principal_type"
+	dashboardAccessPrincipalIDKey   = "principal_id"
+	dashboardAccessLevelKey         = "access_level"
+)
+
+// keyedMutex serializes the read-modify-write cycles on the same key
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks key and returns the function that unlocks it
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*sync.Mutex)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		k.locks[key] = l
+	}
+	k.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// dashboardLocks is held while the resource accesses of a dashboard are updated, since the API
+// only replaces the whole list and Terraform applies the access resources in parallel
+var dashboardLocks keyedMutex
+
+func resourceDashboardAccess() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDashboardAccessCreate,
+		ReadContext:   resourceDashboardAccessRead,
+		UpdateContext: resourceDashboardAccessUpdate,
+		DeleteContext: resourceDashboardAccessDelete,
+		Description:   "Grants a user or a team access to an EdgeDelta dashboard. The dashboard must set manage_resource_accesses to false.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"dashboard_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+				Description:  "ID of the dashboard.",
+			},
+			"principal_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"user", "team"}, false),
+				Description:  "Kind of principal the access is granted to (user, team).",
+			},
+			"principal_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "ID of the user or the team.",
+			},
+			"access_level": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"viewer", "editor", "owner"}, false),
+				Description:  "Access level of the principal (viewer, editor, owner).",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDashboardAccessImport,
+		},
+	}
+}
+
+// dashboardAccessID returns the resource ID of an access, "<dashboard_id>/<principal_type>/<principal_id>"
+func dashboardAccessID(dashboardID, principalType, principalID string) string {
+	return fmt.Sprintf("%s/%s/%s", dashboardID, principalType, principalID)
+}
+
+// parseDashboardAccessID splits a resource ID built by dashboardAccessID
+func parseDashboardAccessID(id string) (dashboardID, principalType, principalID string, err error) {
+	parts := strings.SplitN(strings.TrimSpace(id), "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid dashboard access ID '%s', expected '<dashboard_id>/<principal_type>/<principal_id>'", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// findDashboardAccess returns the index of the access of the principal, or -1
+func findDashboardAccess(accesses []map[string]interface{}, principalType, principalID string) int {
+	for i, a := range accesses {
+		if a[dashboardAccessPrincipalTypeKey] == principalType && a[dashboardAccessPrincipalIDKey] == principalID {
+			return i
+		}
+	}
+	return -1
+}
+
+// resourceDashboardAccessImport imports an access by its "<dashboard_id>/<principal_type>/<principal_id>" ID
+func resourceDashboardAccessImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	dashboardID, principalType, principalID, err := parseDashboardAccessID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(dashboardAccessID(dashboardID, principalType, principalID))
+	diags := setWithError(d, "dashboard_id", dashboardID, nil)
+	diags = setWithError(d, "principal_type", principalType, diags)
+	diags = setWithError(d, "principal_id", principalID, diags)
+	if len(diags) > 0 {
+		return nil, fmt.Errorf("failed to set dashboard access state: %s", diags[0].Detail)
+	}
+
+	if diags := resourceDashboardAccessRead(ctx, d, m); diags.HasError() {
+		return nil, fmt.Errorf("could not read dashboard access: %s", diags[0].Detail)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("could not find the access of %s '%s' to dashboard '%s'", principalType, principalID, dashboardID)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// updateDashboardAccesses applies modify to the resource accesses of the dashboard, see
+// APIClient.UpdateDashboardAccesses. The lock keeps the access resources of the same dashboard,
+// which Terraform applies in parallel, from retrying against each other.
+func updateDashboardAccesses(ctx context.Context, cli *APIClient, dashboardID string, modify func([]map[string]interface{}) ([]map[string]interface{}, error)) error {
+	unlock := dashboardLocks.lock(dashboardID)
+	defer unlock()
+	return cli.UpdateDashboardAccesses(ctx, dashboardID, modify)
+}
+
+func resourceDashboardAccessCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	dashboardID := d.Get("dashboard_id").(string)
+	principalType := d.Get("principal_type").(string)
+	principalID := d.Get("principal_id").(string)
+	err := updateDashboardAccesses(ctx, meta.client, dashboardID, func(accesses []map[string]interface{}) ([]map[string]interface{}, error) {
+		if findDashboardAccess(accesses, principalType, principalID) >= 0 {
+			return nil, fmt.Errorf("%s '%s' already has access to the dashboard, import it with ID '%s'",
+				principalType, principalID, dashboardAccessID(dashboardID, principalType, principalID))
+		}
+		return append(accesses, map[string]interface{}{
+			dashboardAccessPrincipalTypeKey: principalType,
+			dashboardAccessPrincipalIDKey:   principalID,
+			dashboardAccessLevelKey:         d.Get("access_level").(string),
+		}), nil
+	})
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not create the dashboard access resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId(dashboardAccessID(dashboardID, principalType, principalID))
+	return diags
+}
+
+func resourceDashboardAccessRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	dashboardID := d.Get("dashboard_id").(string)
+	accesses, err := meta.client.GetDashboardAccesses(ctx, dashboardID)
+	if err != nil {
+		// Check if the dashboard was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not read the dashboard access resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	principalType := d.Get("principal_type").(string)
+	principalID := d.Get("principal_id").(string)
+	i := findDashboardAccess(accesses, principalType, principalID)
+	if i < 0 {
+		// The access was removed outside this resource. The usual cause is the edgedelta_dashboard
+		// resource of the dashboard, which the provider can't see from here
+		d.SetId("")
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "The dashboard access was removed outside of this resource",
+			Detail: fmt.Sprintf("The access of %s '%s' to dashboard '%s' no longer exists and will be granted again. "+
+				"If the dashboard is managed by an edgedelta_dashboard resource, set manage_resource_accesses = false on it, "+
+				"otherwise it removes the access on every apply.", principalType, principalID, dashboardID),
+		})
+	}
+	level, _ := accesses[i][dashboardAccessLevelKey].(string)
+	return setWithError(d, "access_level", level, diags)
+}
+
+func resourceDashboardAccessUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	dashboardID := d.Get("dashboard_id").(string)
+	principalType := d.Get("principal_type").(string)
+	principalID := d.Get("principal_id").(string)
+	err := updateDashboardAccesses(ctx, meta.client, dashboardID, func(accesses []map[string]interface{}) ([]map[string]interface{}, error) {
+		i := findDashboardAccess(accesses, principalType, principalID)
+		if i < 0 {
+			return nil, fmt.Errorf("%s '%s' no longer has access to the dashboard", principalType, principalID)
+		}
+		accesses[i][dashboardAccessLevelKey] = d.Get("access_level").(string)
+		return accesses, nil
+	})
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not update the dashboard access resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	return diags
+}
+
+func resourceDashboardAccessDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	principalType := d.Get("principal_type").(string)
+	principalID := d.Get("principal_id").(string)
+	err := updateDashboardAccesses(ctx, meta.client, d.Get("dashboard_id").(string), func(accesses []map[string]interface{}) ([]map[string]interface{}, error) {
+		if i := findDashboardAccess(accesses, principalType, principalID); i >= 0 {
+			accesses = append(accesses[:i], accesses[i+1:]...)
+		}
+		return accesses, nil
+	})
+	if err != nil {
+		// If the dashboard is already deleted, so is the access
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not delete the dashboard access resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId("")
+	return diags
+}
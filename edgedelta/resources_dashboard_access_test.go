@@ -0,0 +1,168 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newDashboardAccessServer serves a single dashboard whose accesses are replaced by every PUT
+func newDashboardAccessServer(t *testing.T, dashboard *Dashboard) *httptest.Server {
+	var mu sync.Mutex
+	return newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut {
+			var updated Dashboard
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Errorf("failed to decode request body: %v", err)
+			}
+			*dashboard = updated
+		}
+		_ = json.NewEncoder(w).Encode(dashboard)
+	})
+}
+
+func TestResourceDashboardAccessCreate_Concurrent(t *testing.T) {
+	dashboard := &Dashboard{
+		DashboardID:      testDashboardID,
+		DashboardName:    "Test Dashboard",
+		Definition:       map[string]interface{}{"widgets": []interface{}{}},
+		ResourceAccesses: []map[string]interface{}{{"principal_type": "team", "principal_id": "sre", "access_level": "owner"}},
+	}
+	server := newDashboardAccessServer(t, dashboard)
+	defer server.Close()
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d := schema.TestResourceDataRaw(t, resourceDashboardAccess().Schema, map[string]interface{}{
+				"dashboard_id":   testDashboardID,
+				"principal_type": "user",
+				"principal_id":   fmt.Sprintf("user-%d", i),
+				"access_level":   "viewer",
+			})
+			if diags := resourceDashboardAccessCreate(context.Background(), d, newTestProviderMetadata(server.URL)); len(diags) > 0 {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(dashboard.ResourceAccesses) != n+1 {
+		t.Fatalf("expected %d accesses, got %d: %v", n+1, len(dashboard.ResourceAccesses), dashboard.ResourceAccesses)
+	}
+	if dashboard.Definition == nil {
+		t.Error("expected the dashboard definition to be kept")
+	}
+}
+
+func TestResourceDashboardAccessCreate_AlreadyExists(t *testing.T) {
+	dashboard := &Dashboard{
+		DashboardID:      testDashboardID,
+		ResourceAccesses: []map[string]interface{}{{"principal_type": "user", "principal_id": testUserID, "access_level": "viewer"}},
+	}
+	server := newDashboardAccessServer(t, dashboard)
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDashboardAccess().Schema, map[string]interface{}{
+		"dashboard_id":   testDashboardID,
+		"principal_type": "user",
+		"principal_id":   testUserID,
+		"access_level":   "editor",
+	})
+	if diags := resourceDashboardAccessCreate(context.Background(), d, newTestProviderMetadata(server.URL)); !diags.HasError() {
+		t.Fatal("expected an error for an existing access")
+	}
+	if got := dashboard.ResourceAccesses[0]["access_level"]; got != "viewer" {
+		t.Errorf("expected the existing access to be unchanged, got %v", got)
+	}
+}
+
+func TestResourceDashboardAccessUpdateDelete(t *testing.T) {
+	dashboard := &Dashboard{
+		DashboardID: testDashboardID,
+		ResourceAccesses: []map[string]interface{}{
+			{"principal_type": "user", "principal_id": testUserID, "access_level": "viewer", "granted_by": "admin"},
+			{"principal_type": "team", "principal_id": testTeamID, "access_level": "editor"},
+		},
+	}
+	server := newDashboardAccessServer(t, dashboard)
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDashboardAccess().Schema, map[string]interface{}{
+		"dashboard_id":   testDashboardID,
+		"principal_type": "user",
+		"principal_id":   testUserID,
+		"access_level":   "owner",
+	})
+	d.SetId(dashboardAccessID(testDashboardID, "user", testUserID))
+
+	if diags := resourceDashboardAccessUpdate(context.Background(), d, newTestProviderMetadata(server.URL)); len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := dashboard.ResourceAccesses[0]; got["access_level"] != "owner" || got["granted_by"] != "admin" {
+		t.Errorf("expected the access level to be updated and unknown keys kept, got %v", got)
+	}
+
+	if diags := resourceDashboardAccessDelete(context.Background(), d, newTestProviderMetadata(server.URL)); len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(dashboard.ResourceAccesses) != 1 || dashboard.ResourceAccesses[0]["principal_id"] != testTeamID {
+		t.Errorf("expected only the team access to be left, got %v", dashboard.ResourceAccesses)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the ID to be cleared, got %q", d.Id())
+	}
+}
+
+func TestResourceDashboardAccessRead_Removed(t *testing.T) {
+	dashboard := &Dashboard{DashboardID: testDashboardID}
+	server := newDashboardAccessServer(t, dashboard)
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDashboardAccess().Schema, map[string]interface{}{
+		"dashboard_id":   testDashboardID,
+		"principal_type": "user",
+		"principal_id":   testUserID,
+		"access_level":   "viewer",
+	})
+	d.SetId(dashboardAccessID(testDashboardID, "user", testUserID))
+
+	diags := resourceDashboardAccessRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the removed access to be dropped from state, got ID %q", d.Id())
+	}
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail, "manage_resource_accesses = false") {
+		t.Errorf("expected a warning about the dashboard resource, got %v", diags)
+	}
+}
+
+func TestParseDashboardAccessID(t *testing.T) {
+	dashboardID, principalType, principalID, err := parseDashboardAccessID(testDashboardID + "/team/" + testTeamID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dashboardID != testDashboardID || principalType != "team" || principalID != testTeamID {
+		t.Errorf("unexpected parts %q, %q, %q", dashboardID, principalType, principalID)
+	}
+	for _, id := range []string{"", testDashboardID, testDashboardID + "/team", testDashboardID + "//" + testTeamID} {
+		if _, _, _, err := parseDashboardAccessID(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
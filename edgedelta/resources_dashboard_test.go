@@ -0,0 +1,199 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceDashboardRead_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "dashboard not found"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDashboard().Schema, map[string]interface{}{
+		"dashboard_name": "Test Dashboard",
+	})
+	d.SetId(testDashboardID)
+
+	diags := resourceDashboardRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the dashboard to be removed from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceDashboardRead_ServerError(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error": "dashboard store not found in cache"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDashboard().Schema, map[string]interface{}{
+		"dashboard_name": "Test Dashboard",
+	})
+	d.SetId(testDashboardID)
+
+	diags := resourceDashboardRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if !diags.HasError() {
+		t.Fatal("expected an error for a 500 response")
+	}
+	if d.Id() != testDashboardID {
+		t.Errorf("expected the dashboard to stay in state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceDashboardDelete_DeletionProtection(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no API request, got %s %s", r.Method, r.URL.Path)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDashboard().Schema, map[string]interface{}{
+		"dashboard_name":      "Test Dashboard",
+		"deletion_protection": true,
+	})
+	d.SetId(testDashboardID)
+
+	diags := resourceDashboardDelete(context.Background(), d, newTestProviderMetadata(server.URL))
+	if !diags.HasError() {
+		t.Fatal("expected an error for a protected dashboard")
+	}
+	if d.Id() != testDashboardID {
+		t.Errorf("expected the dashboard to stay in state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceDashboardUpdate_KeepsUnmanagedAccesses(t *testing.T) {
+	accesses := []map[string]interface{}{{"principal_type": "team", "principal_id": "sre", "access_level": "editor"}}
+	var updated Dashboard
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Errorf("failed to decode request body: %v", err)
+			}
+			updated.DashboardID = testDashboardID
+			_ = json.NewEncoder(w).Encode(updated)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Dashboard{DashboardID: testDashboardID, DashboardName: "Old", ResourceAccesses: accesses})
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDashboard().Schema, map[string]interface{}{
+		"dashboard_name":           "Test Dashboard",
+		"definition":               `{"definition": {"widgets": []}}`,
+		"manage_resource_accesses": false,
+		"sharing":                  []interface{}{map[string]interface{}{"public": true, "allowed_domains": []interface{}{"example.com"}}},
+	})
+	d.SetId(testDashboardID)
+
+	diags := resourceDashboardUpdate(context.Background(), d, newTestProviderMetadata(server.URL))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !reflect.DeepEqual(updated.ResourceAccesses, accesses) {
+		t.Errorf("expected the existing accesses to be kept, got %v", updated.ResourceAccesses)
+	}
+	if updated.SharingSecuritySettings["public"] != true {
+		t.Errorf("expected the sharing block to be sent, got %v", updated.SharingSecuritySettings)
+	}
+	if def := d.Get("definition").(string); strings.Contains(def, "resource_accesses") || strings.Contains(def, "sharing_security_settings") {
+		t.Errorf("expected the fields managed elsewhere to be left out of the definition, got %s", def)
+	}
+	sharing := d.Get("sharing").([]interface{})[0].(map[string]interface{})
+	if domains := sharing["allowed_domains"].([]interface{}); len(domains) != 1 || domains[0] != "example.com" {
+		t.Errorf("unexpected allowed_domains %v", domains)
+	}
+}
+
+func TestResourceDashboardDiff_ManagedElsewhere(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "accesses in definition",
+			raw:  map[string]interface{}{"definition": `{"resource_accesses": []}`},
+		},
+		{
+			name:    "unmanaged accesses in definition",
+			raw:     map[string]interface{}{"definition": `{"resource_accesses": []}`, "manage_resource_accesses": false},
+			wantErr: "resource_accesses",
+		},
+		{
+			name: "sharing block",
+			raw:  map[string]interface{}{"definition": `{"definition": {}}`, "sharing": []interface{}{map[string]interface{}{"public": true}}},
+		},
+		{
+			name:    "sharing block and settings in definition",
+			raw:     map[string]interface{}{"definition": `{"sharing_security_settings": {}}`, "sharing": []interface{}{map[string]interface{}{"public": true}}},
+			wantErr: "sharing_security_settings",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.raw["dashboard_name"] = "Test Dashboard"
+			_, err := resourceDashboard().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.raw), nil)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected an error about %s, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResourceDashboardRead_SharingSettings(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Dashboard{
+			DashboardID:             testDashboardID,
+			DashboardName:           "Test Dashboard",
+			Definition:              map[string]interface{}{"widgets": []interface{}{}},
+			SharingSecuritySettings: map[string]interface{}{"public": true},
+		})
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		definition  string
+		wantSharing bool
+	}{
+		{name: "definition without sharing settings", definition: `{"definition": {"widgets": []}}`, wantSharing: false},
+		{name: "definition with sharing settings", definition: `{"definition": {"widgets": []}, "sharing_security_settings": {"public": false}}`, wantSharing: true},
+		{name: "imported", definition: "", wantSharing: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{"dashboard_name": "Test Dashboard"}
+			if tt.definition != "" {
+				raw["definition"] = tt.definition
+			}
+			d := schema.TestResourceDataRaw(t, resourceDashboard().Schema, raw)
+			d.SetId(testDashboardID)
+
+			if diags := resourceDashboardRead(context.Background(), d, newTestProviderMetadata(server.URL)); len(diags) > 0 {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if got := strings.Contains(d.Get("definition").(string), "sharing_security_settings"); got != tt.wantSharing {
+				t.Errorf("expected sharing settings in the definition to be %t, got definition %s", tt.wantSharing, d.Get("definition"))
+			}
+		})
+	}
+}
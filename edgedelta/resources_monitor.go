@@ -0,0 +1,403 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	monitorOperators     = []string{">", ">=", "<", "<=", "==", "!="}
+	monitorSensitivities = []string{"low", "medium", "high"}
+	monitorDirections    = []string{"above", "below", "both"}
+	monitorSeverities    = []string{"info", "warning", "error", "critical"}
+)
+
+func resourceMonitor() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceMonitorCreate,
+		ReadContext:   resourceMonitorRead,
+		UpdateContext: resourceMonitorUpdate,
+		DeleteContext: resourceMonitorDelete,
+		Description:   "Manages an EdgeDelta monitor resource.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the monitor.",
+			},
+			"query": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Query whose result the monitor evaluates.",
+			},
+
+			// Optional
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the monitor.",
+			},
+			"threshold": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: []string{"threshold", "anomaly"},
+				Description:  "Triggers when the query result crosses a static value.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"operator": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(monitorOperators, false),
+							Description:  "Comparison of the query result with value (>, >=, <, <=, ==, !=).",
+						},
+						"value": {
+							Type:        schema.TypeFloat,
+							Required:    true,
+							Description: "Value the query result is compared with.",
+						},
+					},
+				},
+			},
+			"anomaly": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: []string{"threshold", "anomaly"},
+				Description:  "Triggers when the query result deviates from its baseline.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sensitivity": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "medium",
+							ValidateFunc: validation.StringInSlice(monitorSensitivities, false),
+							Description:  "How far the result must deviate from the baseline (low, medium, high).",
+						},
+						"direction": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "both",
+							ValidateFunc: validation.StringInSlice(monitorDirections, false),
+							Description:  "Which deviations trigger the monitor (above, below, both).",
+						},
+					},
+				},
+			},
+			"evaluation_window": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+				Description:  "Time window the query is evaluated over, as a duration. Defaults to 5m.",
+			},
+			"severity": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "warning",
+				ValidateFunc: validation.StringInSlice(monitorSeverities, false),
+				Description:  "Severity of the alerts (info, warning, error, critical). Defaults to warning.",
+			},
+			"notification_targets": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the notification channels that receive the alerts.",
+			},
+			"muted": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Evaluate the monitor without sending notifications.",
+			},
+			"tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Searchable tags for the monitor.",
+			},
+
+			// Computed
+			"monitor_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Unique identifier for the monitor.",
+			},
+			"creator": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "User ID who created the monitor.",
+			},
+			"updater": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "User ID who last updated the monitor.",
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of monitor creation.",
+			},
+			"updated": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of last update.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceMonitorImport,
+		},
+	}
+}
+
+// resourceMonitorImport imports a single monitor ID, a comma-separated list of IDs or every monitor ("*")
+func resourceMonitorImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	meta := m.(*ProviderMetadata)
+	monitorID := strings.TrimSpace(d.Id())
+	if monitorID == "" {
+		return nil, fmt.Errorf("could not determine the resource ID - possibly the ID was not set")
+	}
+
+	var monitors []*Monitor
+	if monitorID == "*" {
+		var err error
+		monitors, err = meta.client.GetAllMonitors(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get monitors from API: %s", err)
+		}
+	} else {
+		for _, id := range strings.Split(monitorID, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			resp, err := meta.client.GetMonitor(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("could not get monitor from API: %s (monitor ID was: '%s')", err, id)
+			}
+			monitors = append(monitors, (*Monitor)(resp))
+		}
+	}
+
+	results := make([]*schema.ResourceData, 0, len(monitors))
+	for _, mon := range monitors {
+		dd := resourceMonitor().Data(nil)
+		dd.SetId(mon.MonitorID)
+		if err := setMonitorState(dd, mon); err != nil {
+			return nil, fmt.Errorf("failed to set monitor state: %s", err)
+		}
+		results = append(results, dd)
+	}
+	return results, nil
+}
+
+// buildMonitor builds the API representation of the monitor from the resource data
+func buildMonitor(d *schema.ResourceData) *Monitor {
+	mon := &Monitor{
+		Name:                d.Get("name").(string),
+		Description:         d.Get("description").(string),
+		Query:               d.Get("query").(string),
+		EvaluationWindow:    d.Get("evaluation_window").(string),
+		Severity:            d.Get("severity").(string),
+		NotificationTargets: interfaceSliceToStringSlice(d.Get("notification_targets").([]interface{})),
+		Muted:               d.Get("muted").(bool),
+		Tags:                interfaceSliceToStringSlice(d.Get("tags").([]interface{})),
+	}
+	if v, ok := d.GetOk("threshold"); ok {
+		if raw, ok := v.([]interface{})[0].(map[string]interface{}); ok {
+			mon.Type = ThresholdMonitorType
+			mon.Threshold = &MonitorThreshold{
+				Operator: raw["operator"].(string),
+				Value:    raw["value"].(float64),
+			}
+		}
+	}
+	if v, ok := d.GetOk("anomaly"); ok {
+		mon.Type = AnomalyMonitorType
+		mon.Anomaly = &MonitorAnomaly{Sensitivity: "medium", Direction: "both"}
+		// An empty anomaly {} block is read back as a nil element
+		if raw, ok := v.([]interface{})[0].(map[string]interface{}); ok {
+			mon.Anomaly.Sensitivity = raw["sensitivity"].(string)
+			mon.Anomaly.Direction = raw["direction"].(string)
+		}
+	}
+	return mon
+}
+
+func setMonitorState(d *schema.ResourceData, mon *Monitor) error {
+	if err := d.Set("monitor_id", mon.MonitorID); err != nil {
+		return err
+	}
+	if err := d.Set("name", mon.Name); err != nil {
+		return err
+	}
+	if err := d.Set("description", mon.Description); err != nil {
+		return err
+	}
+	if err := d.Set("query", mon.Query); err != nil {
+		return err
+	}
+
+	var threshold, anomaly []interface{}
+	if mon.Threshold != nil {
+		threshold = []interface{}{map[string]interface{}{
+			"operator": mon.Threshold.Operator,
+			"value":    mon.Threshold.Value,
+		}}
+	}
+	if mon.Anomaly != nil {
+		anomaly = []interface{}{map[string]interface{}{
+			"sensitivity": mon.Anomaly.Sensitivity,
+			"direction":   mon.Anomaly.Direction,
+		}}
+	}
+	if err := d.Set("threshold", threshold); err != nil {
+		return err
+	}
+	if err := d.Set("anomaly", anomaly); err != nil {
+		return err
+	}
+
+	if mon.EvaluationWindow != "" {
+		if err := d.Set("evaluation_window", mon.EvaluationWindow); err != nil {
+			return err
+		}
+	}
+	if mon.Severity != "" {
+		if err := d.Set("severity", mon.Severity); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("notification_targets", stringSliceToInterface(mon.NotificationTargets)); err != nil {
+		return err
+	}
+	if err := d.Set("muted", mon.Muted); err != nil {
+		return err
+	}
+	if err := d.Set("tags", stringSliceToInterface(mon.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("creator", mon.Creator); err != nil {
+		return err
+	}
+	if err := d.Set("updater", mon.Updater); err != nil {
+		return err
+	}
+	if err := d.Set("created", mon.Created); err != nil {
+		return err
+	}
+	if err := d.Set("updated", mon.Updated); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceMonitorCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.CreateMonitor(ctx, buildMonitor(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not create the monitor resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId(resp.MonitorID)
+	if err := setMonitorState(d, (*Monitor)(resp)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Failed to set monitor state after create",
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+func resourceMonitorRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.GetMonitor(ctx, d.Id())
+	if err != nil {
+		// Check if resource was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not read the monitor resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	if err := setMonitorState(d, (*Monitor)(resp)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Failed to set monitor state after read",
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+func resourceMonitorUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.UpdateMonitor(ctx, d.Id(), buildMonitor(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not update the monitor resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	if err := setMonitorState(d, (*Monitor)(resp)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Failed to set monitor state after update",
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+func resourceMonitorDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	err := meta.client.DeleteMonitor(ctx, d.Id())
+	if err != nil {
+		// If already deleted, just remove from state
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not delete the monitor resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId("")
+	return diags
+}
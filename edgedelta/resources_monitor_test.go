@@ -0,0 +1,151 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestBuildMonitor_Threshold(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceMonitor().Schema, map[string]interface{}{
+		"name":                 "High error rate",
+		"query":                "sum(errors)",
+		"threshold":            []interface{}{map[string]interface{}{"operator": ">=", "value": 2.5}},
+		"notification_targets": []interface{}{"channel-1", "channel-2"},
+	})
+
+	mon := buildMonitor(d)
+	if mon.Type != ThresholdMonitorType {
+		t.Errorf("expected type %s, got %s", ThresholdMonitorType, mon.Type)
+	}
+	if mon.Threshold == nil || mon.Threshold.Operator != ">=" || mon.Threshold.Value != 2.5 {
+		t.Errorf("unexpected threshold: %+v", mon.Threshold)
+	}
+	if mon.Anomaly != nil {
+		t.Errorf("expected no anomaly condition, got %+v", mon.Anomaly)
+	}
+	if mon.EvaluationWindow != "5m" || mon.Severity != "warning" {
+		t.Errorf("expected the defaults 5m/warning, got %s/%s", mon.EvaluationWindow, mon.Severity)
+	}
+	if len(mon.NotificationTargets) != 2 {
+		t.Errorf("expected 2 notification targets, got %v", mon.NotificationTargets)
+	}
+}
+
+func TestBuildMonitor_Anomaly(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceMonitor().Schema, map[string]interface{}{
+		"name":    "Traffic anomaly",
+		"query":   "sum(requests)",
+		"anomaly": []interface{}{map[string]interface{}{"direction": "below"}},
+	})
+
+	mon := buildMonitor(d)
+	if mon.Type != AnomalyMonitorType {
+		t.Errorf("expected type %s, got %s", AnomalyMonitorType, mon.Type)
+	}
+	if mon.Anomaly == nil || mon.Anomaly.Sensitivity != "medium" || mon.Anomaly.Direction != "below" {
+		t.Errorf("unexpected anomaly condition: %+v", mon.Anomaly)
+	}
+	if mon.Threshold != nil {
+		t.Errorf("expected no threshold, got %+v", mon.Threshold)
+	}
+}
+
+func TestResourceMonitorRead(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Monitor{
+			MonitorID: testMonitorID,
+			Name:      "Changed outside Terraform",
+			Type:      AnomalyMonitorType,
+			Query:     "sum(requests)",
+			Anomaly:   &MonitorAnomaly{Sensitivity: "low", Direction: "both"},
+			Muted:     true,
+		})
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceMonitor().Schema, map[string]interface{}{
+		"name":      "High error rate",
+		"query":     "sum(errors)",
+		"threshold": []interface{}{map[string]interface{}{"operator": ">", "value": 100.0}},
+	})
+	d.SetId(testMonitorID)
+
+	diags := resourceMonitorRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := d.Get("name").(string); got != "Changed outside Terraform" {
+		t.Errorf("expected the name from the API, got %q", got)
+	}
+	if got := d.Get("threshold").([]interface{}); len(got) != 0 {
+		t.Errorf("expected the threshold to be removed, got %v", got)
+	}
+	if got := d.Get("anomaly.0.sensitivity").(string); got != "low" {
+		t.Errorf("expected sensitivity low, got %q", got)
+	}
+	if !d.Get("muted").(bool) {
+		t.Error("expected the monitor to be muted")
+	}
+	// Fields the API leaves empty keep their defaults
+	if got := d.Get("evaluation_window").(string); got != "5m" {
+		t.Errorf("expected evaluation_window 5m, got %q", got)
+	}
+}
+
+func TestResourceMonitorRead_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "monitor not found"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceMonitor().Schema, map[string]interface{}{
+		"name":  "High error rate",
+		"query": "sum(errors)",
+	})
+	d.SetId(testMonitorID)
+
+	diags := resourceMonitorRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the monitor to be removed from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceMonitorImport_All(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/orgs/"+testOrgID+"/monitors" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*Monitor{
+			{MonitorID: testMonitorID, Name: "Monitor 1", Query: "a", Threshold: &MonitorThreshold{Operator: ">", Value: 1}},
+			{MonitorID: "770e8400-e29b-41d4-a716-446655440003", Name: "Monitor 2", Query: "b", Anomaly: &MonitorAnomaly{Sensitivity: "high", Direction: "above"}},
+		})
+	})
+	defer server.Close()
+
+	d := resourceMonitor().Data(nil)
+	d.SetId("*")
+
+	results, err := resourceMonitorImport(context.Background(), d, newTestProviderMetadata(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 monitors, got %d", len(results))
+	}
+	if results[0].Id() != testMonitorID || results[0].Get("threshold.0.operator").(string) != ">" {
+		t.Errorf("unexpected first monitor: %s %v", results[0].Id(), results[0].Get("threshold"))
+	}
+	if got := results[1].Get("anomaly.0.direction").(string); got != "above" {
+		t.Errorf("expected direction above, got %q", got)
+	}
+}
@@ -0,0 +1,472 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var notificationChannelBlocks = []string{"slack", "pagerduty", "teams", "webhook", "email"}
+
+var emailAddressRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+func resourceNotificationChannel() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNotificationChannelCreate,
+		ReadContext:   resourceNotificationChannelRead,
+		UpdateContext: resourceNotificationChannelUpdate,
+		DeleteContext: resourceNotificationChannelDelete,
+		Description:   "Manages an EdgeDelta notification channel that alerts are sent to.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the notification channel.",
+			},
+
+			// Optional
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the notification channel.",
+			},
+			"slack": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: notificationChannelBlocks,
+				Description:  "Sends alerts to a Slack incoming webhook.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"webhook_url": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validation.IsURLWithHTTPS,
+							Description:  "Slack incoming webhook URL.",
+						},
+						"channel": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Channel to post to instead of the default channel of the webhook, e.g. \"#alerts\".",
+						},
+					},
+				},
+			},
+			"pagerduty": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: notificationChannelBlocks,
+				Description:  "Sends alerts to a PagerDuty service.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"routing_key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+							Description:  "Events API v2 integration key of the PagerDuty service.",
+						},
+					},
+				},
+			},
+			"teams": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: notificationChannelBlocks,
+				Description:  "Sends alerts to a Microsoft Teams incoming webhook.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"webhook_url": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validation.IsURLWithHTTPS,
+							Description:  "Microsoft Teams incoming webhook URL.",
+						},
+					},
+				},
+			},
+			"webhook": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: notificationChannelBlocks,
+				Description:  "Sends alerts as JSON to a generic HTTP endpoint.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"url": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+							Description:  "URL of the endpoint.",
+						},
+						"method": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "POST",
+							ValidateFunc: validation.StringInSlice([]string{"POST", "PUT"}, false),
+							Description:  "HTTP method of the requests (POST, PUT). Defaults to POST.",
+						},
+						"headers": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Sensitive:   true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "HTTP headers of the requests, e.g. for authentication.",
+						},
+					},
+				},
+			},
+			"email": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: notificationChannelBlocks,
+				Description:  "Sends alerts by email.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"addresses": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringMatch(emailAddressRegexp, "must be an email address"),
+							},
+							Description: "Email addresses of the recipients.",
+						},
+					},
+				},
+			},
+
+			// Computed
+			"channel_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Unique identifier for the notification channel.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the notification channel, derived from the configured block.",
+			},
+			"creator": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "User ID who created the notification channel.",
+			},
+			"updater": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "User ID who last updated the notification channel.",
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of notification channel creation.",
+			},
+			"updated": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of last update.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceNotificationChannelImport,
+		},
+	}
+}
+
+// resourceNotificationChannelImport imports a single channel ID, a comma-separated list of IDs or every channel ("*").
+// The API doesn't return secrets, so they are only known after the next apply.
+func resourceNotificationChannelImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	meta := m.(*ProviderMetadata)
+	channelID := strings.TrimSpace(d.Id())
+	if channelID == "" {
+		return nil, fmt.Errorf("could not determine the resource ID - possibly the ID was not set")
+	}
+
+	var channels []*NotificationChannel
+	if channelID == "*" {
+		var err error
+		channels, err = meta.client.GetAllNotificationChannels(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get notification channels from API: %s", err)
+		}
+	} else {
+		for _, id := range strings.Split(channelID, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			resp, err := meta.client.GetNotificationChannel(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("could not get notification channel from API: %s (channel ID was: '%s')", err, id)
+			}
+			channels = append(channels, (*NotificationChannel)(resp))
+		}
+	}
+
+	results := make([]*schema.ResourceData, 0, len(channels))
+	for _, ch := range channels {
+		dd := resourceNotificationChannel().Data(nil)
+		dd.SetId(ch.ChannelID)
+		if err := setNotificationChannelState(dd, ch); err != nil {
+			return nil, fmt.Errorf("failed to set notification channel state: %s", err)
+		}
+		results = append(results, dd)
+	}
+	return results, nil
+}
+
+// notificationChannelBlock returns the attributes of the single-item block key, or nil if it is not set
+func notificationChannelBlock(d *schema.ResourceData, key string) map[string]interface{} {
+	v, ok := d.GetOk(key)
+	if !ok {
+		return nil
+	}
+	raw, _ := v.([]interface{})[0].(map[string]interface{})
+	return raw
+}
+
+// buildNotificationChannel builds the API representation of the notification channel from the resource data
+func buildNotificationChannel(d *schema.ResourceData) *NotificationChannel {
+	ch := &NotificationChannel{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}
+	if raw := notificationChannelBlock(d, "slack"); raw != nil {
+		ch.Type = SlackNotificationChannelType
+		ch.Slack = &SlackChannelSettings{
+			WebhookURL: raw["webhook_url"].(string),
+			Channel:    raw["channel"].(string),
+		}
+	}
+	if raw := notificationChannelBlock(d, "pagerduty"); raw != nil {
+		ch.Type = PagerDutyNotificationChannelType
+		ch.PagerDuty = &PagerDutyChannelSettings{RoutingKey: raw["routing_key"].(string)}
+	}
+	if raw := notificationChannelBlock(d, "teams"); raw != nil {
+		ch.Type = TeamsNotificationChannelType
+		ch.Teams = &TeamsChannelSettings{WebhookURL: raw["webhook_url"].(string)}
+	}
+	if raw := notificationChannelBlock(d, "webhook"); raw != nil {
+		ch.Type = WebhookNotificationChannelType
+		ch.Webhook = &WebhookChannelSettings{
+			URL:    raw["url"].(string),
+			Method: raw["method"].(string),
+		}
+		if headers, ok := raw["headers"].(map[string]interface{}); ok && len(headers) > 0 {
+			ch.Webhook.Headers = make(map[string]string, len(headers))
+			for k, v := range headers {
+				ch.Webhook.Headers[k] = v.(string)
+			}
+		}
+	}
+	if raw := notificationChannelBlock(d, "email"); raw != nil {
+		ch.Type = EmailNotificationChannelType
+		ch.Email = &EmailChannelSettings{Addresses: interfaceSliceToStringSlice(raw["addresses"].([]interface{}))}
+	}
+	return ch
+}
+
+// channelSecret returns the secret returned by the API, unless it is empty or masked,
+// in which case the value in state is kept so that write-only secrets don't show as drift
+func channelSecret(apiValue, stateValue string) string {
+	if apiValue == "" || strings.Contains(apiValue, "***") {
+		return stateValue
+	}
+	return apiValue
+}
+
+func setNotificationChannelState(d *schema.ResourceData, ch *NotificationChannel) error {
+	if err := d.Set("channel_id", ch.ChannelID); err != nil {
+		return err
+	}
+	if err := d.Set("name", ch.Name); err != nil {
+		return err
+	}
+	if err := d.Set("description", ch.Description); err != nil {
+		return err
+	}
+	if err := d.Set("type", string(ch.Type)); err != nil {
+		return err
+	}
+
+	blocks := make(map[string][]interface{}, len(notificationChannelBlocks))
+	if ch.Slack != nil {
+		blocks["slack"] = []interface{}{map[string]interface{}{
+			"webhook_url": channelSecret(ch.Slack.WebhookURL, d.Get("slack.0.webhook_url").(string)),
+			"channel":     ch.Slack.Channel,
+		}}
+	}
+	if ch.PagerDuty != nil {
+		blocks["pagerduty"] = []interface{}{map[string]interface{}{
+			"routing_key": channelSecret(ch.PagerDuty.RoutingKey, d.Get("pagerduty.0.routing_key").(string)),
+		}}
+	}
+	if ch.Teams != nil {
+		blocks["teams"] = []interface{}{map[string]interface{}{
+			"webhook_url": channelSecret(ch.Teams.WebhookURL, d.Get("teams.0.webhook_url").(string)),
+		}}
+	}
+	if ch.Webhook != nil {
+		method := ch.Webhook.Method
+		if method == "" {
+			method = "POST"
+		}
+		// Headers may carry credentials too, they are kept from state if the API omits them
+		headers, _ := d.Get("webhook.0.headers").(map[string]interface{})
+		if len(ch.Webhook.Headers) > 0 {
+			stateHeaders := headers
+			headers = make(map[string]interface{}, len(ch.Webhook.Headers))
+			for k, v := range ch.Webhook.Headers {
+				stateValue, _ := stateHeaders[k].(string)
+				headers[k] = channelSecret(v, stateValue)
+			}
+		}
+		blocks["webhook"] = []interface{}{map[string]interface{}{
+			"url":     channelSecret(ch.Webhook.URL, d.Get("webhook.0.url").(string)),
+			"method":  method,
+			"headers": headers,
+		}}
+	}
+	if ch.Email != nil {
+		blocks["email"] = []interface{}{map[string]interface{}{
+			"addresses": stringSliceToInterface(ch.Email.Addresses),
+		}}
+	}
+	for _, key := range notificationChannelBlocks {
+		if err := d.Set(key, blocks[key]); err != nil {
+			return err
+		}
+	}
+
+	if err := d.Set("creator", ch.Creator); err != nil {
+		return err
+	}
+	if err := d.Set("updater", ch.Updater); err != nil {
+		return err
+	}
+	if err := d.Set("created", ch.Created); err != nil {
+		return err
+	}
+	if err := d.Set("updated", ch.Updated); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceNotificationChannelCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.CreateNotificationChannel(ctx, buildNotificationChannel(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not create the notification channel resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId(resp.ChannelID)
+	if err := setNotificationChannelState(d, (*NotificationChannel)(resp)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Failed to set notification channel state after create",
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+func resourceNotificationChannelRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.GetNotificationChannel(ctx, d.Id())
+	if err != nil {
+		// Check if resource was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not read the notification channel resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	if err := setNotificationChannelState(d, (*NotificationChannel)(resp)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Failed to set notification channel state after read",
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+func resourceNotificationChannelUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.UpdateNotificationChannel(ctx, d.Id(), buildNotificationChannel(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not update the notification channel resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	if err := setNotificationChannelState(d, (*NotificationChannel)(resp)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Failed to set notification channel state after update",
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+func resourceNotificationChannelDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	err := meta.client.DeleteNotificationChannel(ctx, d.Id())
+	if err != nil {
+		// If already deleted, just remove from state
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not delete the notification channel resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId("")
+	return diags
+}
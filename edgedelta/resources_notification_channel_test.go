@@ -0,0 +1,189 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestBuildNotificationChannel(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      map[string]interface{}
+		wantType NotificationChannelType
+		check    func(t *testing.T, ch *NotificationChannel)
+	}{
+		{
+			name: "slack",
+			raw: map[string]interface{}{
+				"slack": []interface{}{map[string]interface{}{"webhook_url": "https://hooks.slack.com/services/T/B/X", "channel": "#alerts"}},
+			},
+			wantType: SlackNotificationChannelType,
+			check: func(t *testing.T, ch *NotificationChannel) {
+				if ch.Slack == nil || ch.Slack.Channel != "#alerts" {
+					t.Errorf("unexpected slack settings: %+v", ch.Slack)
+				}
+			},
+		},
+		{
+			name: "webhook",
+			raw: map[string]interface{}{
+				"webhook": []interface{}{map[string]interface{}{
+					"url":     "https://alerts.example.com/hook",
+					"headers": map[string]interface{}{"Authorization": "Bearer secret"},
+				}},
+			},
+			wantType: WebhookNotificationChannelType,
+			check: func(t *testing.T, ch *NotificationChannel) {
+				if ch.Webhook == nil || ch.Webhook.Method != "POST" || ch.Webhook.Headers["Authorization"] != "Bearer secret" {
+					t.Errorf("unexpected webhook settings: %+v", ch.Webhook)
+				}
+			},
+		},
+		{
+			name: "email",
+			raw: map[string]interface{}{
+				"email": []interface{}{map[string]interface{}{"addresses": []interface{}{"oncall@example.com"}}},
+			},
+			wantType: EmailNotificationChannelType,
+			check: func(t *testing.T, ch *NotificationChannel) {
+				if ch.Email == nil || len(ch.Email.Addresses) != 1 {
+					t.Errorf("unexpected email settings: %+v", ch.Email)
+				}
+				if ch.Slack != nil || ch.Webhook != nil {
+					t.Error("expected only the email settings to be set")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.raw["name"] = "Alerts"
+			d := schema.TestResourceDataRaw(t, resourceNotificationChannel().Schema, tt.raw)
+			ch := buildNotificationChannel(d)
+			if ch.Type != tt.wantType {
+				t.Errorf("expected type %s, got %s", tt.wantType, ch.Type)
+			}
+			tt.check(t, ch)
+		})
+	}
+}
+
+func TestResourceNotificationChannelRead_KeepsMaskedSecrets(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(NotificationChannel{
+			ChannelID: testChannelID,
+			Name:      "Hook",
+			Type:      WebhookNotificationChannelType,
+			Webhook: &WebhookChannelSettings{
+				URL:     "https://alerts.example.com/***",
+				Method:  "PUT",
+				Headers: map[string]string{"Authorization": "***", "X-Team": "sre"},
+			},
+		})
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceNotificationChannel().Schema, map[string]interface{}{
+		"name": "Hook",
+		"webhook": []interface{}{map[string]interface{}{
+			"url":     "https://alerts.example.com/hook?token=secret",
+			"headers": map[string]interface{}{"Authorization": "Bearer secret", "X-Team": "ops"},
+		}},
+	})
+	d.SetId(testChannelID)
+
+	diags := resourceNotificationChannelRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := d.Get("webhook.0.url").(string); got != "https://alerts.example.com/hook?token=secret" {
+		t.Errorf("expected the URL to be kept from state, got %q", got)
+	}
+	if got := d.Get("webhook.0.headers.Authorization").(string); got != "Bearer secret" {
+		t.Errorf("expected the masked header to be kept from state, got %q", got)
+	}
+	// Unmasked values are read back, so drift is still detected
+	if got := d.Get("webhook.0.headers.X-Team").(string); got != "sre" {
+		t.Errorf("expected the header from the API, got %q", got)
+	}
+	if got := d.Get("webhook.0.method").(string); got != "PUT" {
+		t.Errorf("expected method PUT, got %q", got)
+	}
+	if got := d.Get("type").(string); got != "webhook" {
+		t.Errorf("expected type webhook, got %q", got)
+	}
+}
+
+func TestResourceNotificationChannelRead_TypeChanged(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(NotificationChannel{
+			ChannelID: testChannelID,
+			Name:      "On-call",
+			Type:      PagerDutyNotificationChannelType,
+			PagerDuty: &PagerDutyChannelSettings{},
+		})
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceNotificationChannel().Schema, map[string]interface{}{
+		"name":  "On-call",
+		"slack": []interface{}{map[string]interface{}{"webhook_url": "https://hooks.slack.com/services/T/B/X"}},
+	})
+	d.SetId(testChannelID)
+
+	diags := resourceNotificationChannelRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := d.Get("slack").([]interface{}); len(got) != 0 {
+		t.Errorf("expected the slack block to be removed, got %v", got)
+	}
+	if got := d.Get("pagerduty").([]interface{}); len(got) != 1 {
+		t.Errorf("expected a pagerduty block, got %v", got)
+	}
+}
+
+func TestResourceNotificationChannelRead_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "notification channel not found"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceNotificationChannel().Schema, map[string]interface{}{
+		"name":  "On-call",
+		"email": []interface{}{map[string]interface{}{"addresses": []interface{}{"oncall@example.com"}}},
+	})
+	d.SetId(testChannelID)
+
+	diags := resourceNotificationChannelRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the channel to be removed from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceNotificationChannelSchema_Sensitive(t *testing.T) {
+	s := resourceNotificationChannel().Schema
+	for _, path := range [][2]string{
+		{"slack", "webhook_url"},
+		{"pagerduty", "routing_key"},
+		{"teams", "webhook_url"},
+		{"webhook", "url"},
+		{"webhook", "headers"},
+	} {
+		block := s[path[0]].Elem.(*schema.Resource)
+		if !block.Schema[path[1]].Sensitive {
+			t.Errorf("expected %s.%s to be sensitive", path[0], path[1])
+		}
+	}
+}
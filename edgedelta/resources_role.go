@@ -0,0 +1,213 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Description:   "Manages a custom EdgeDelta role, a named set of permissions that can be assigned to users.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the role.",
+			},
+			"permissions": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotWhiteSpace},
+				Description: "Permissions granted by the role, e.g. \"pipelines:write\".",
+			},
+
+			// Optional
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the role.",
+			},
+
+			// Computed
+			"role_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Unique identifier for the role, to assign it with edgedelta_user.role.",
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of role creation.",
+			},
+			"updated": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of last update.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRoleImport,
+		},
+	}
+}
+
+// resourceRoleImport imports a single role ID, a comma-separated list of IDs or every custom role ("*").
+// Built-in roles can't be managed, so they are skipped by "*" and rejected by ID.
+func resourceRoleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	meta := m.(*ProviderMetadata)
+	roleID := strings.TrimSpace(d.Id())
+	if roleID == "" {
+		return nil, fmt.Errorf("could not determine the resource ID - possibly the ID was not set")
+	}
+
+	var roles []*Role
+	if roleID == "*" {
+		all, err := meta.client.GetAllRoles(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get roles from API: %s", err)
+		}
+		for _, r := range all {
+			if r != nil && !r.BuiltIn {
+				roles = append(roles, r)
+			}
+		}
+	} else {
+		for _, id := range strings.Split(roleID, ",") {
+			if id = strings.TrimSpace(id); id == "" {
+				continue
+			}
+			resp, err := meta.client.GetRole(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("could not get role from API: %s (role ID was: '%s')", err, id)
+			}
+			if resp.BuiltIn {
+				return nil, fmt.Errorf("role '%s' is a built-in role and can't be managed, assign it by name instead", resp.Name)
+			}
+			roles = append(roles, (*Role)(resp))
+		}
+	}
+
+	results := make([]*schema.ResourceData, 0, len(roles))
+	for _, r := range roles {
+		dd := resourceRole().Data(nil)
+		dd.SetId(r.RoleID)
+		diags := setRoleState(dd, r, nil)
+		if len(diags) > 0 {
+			return nil, fmt.Errorf("failed to set role state: %s", diags[0].Detail)
+		}
+		results = append(results, dd)
+	}
+	return results, nil
+}
+
+func buildRole(d *schema.ResourceData) *Role {
+	return &Role{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Permissions: interfaceSliceToStringSlice(d.Get("permissions").(*schema.Set).List()),
+	}
+}
+
+func setRoleState(d *schema.ResourceData, r *Role, diags diag.Diagnostics) diag.Diagnostics {
+	diags = setWithError(d, "role_id", r.RoleID, diags)
+	diags = setWithError(d, "name", r.Name, diags)
+	diags = setWithError(d, "description", r.Description, diags)
+	diags = setWithError(d, "permissions", stringSliceToInterface(r.Permissions), diags)
+	diags = setWithError(d, "created", r.Created, diags)
+	diags = setWithError(d, "updated", r.Updated, diags)
+	return diags
+}
+
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.CreateRole(ctx, buildRole(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not create the role resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId(resp.RoleID)
+	return setRoleState(d, (*Role)(resp), diags)
+}
+
+func resourceRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.GetRole(ctx, d.Id())
+	if err != nil {
+		// Check if resource was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not read the role resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	return setRoleState(d, (*Role)(resp), diags)
+}
+
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	if !d.HasChanges("name", "description", "permissions") {
+		return diags
+	}
+
+	resp, err := meta.client.UpdateRole(ctx, d.Id(), buildRole(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not update the role resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	return setRoleState(d, (*Role)(resp), diags)
+}
+
+func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+	err := meta.client.DeleteRole(ctx, d.Id())
+	if err != nil {
+		// If already deleted, just remove from state
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not delete the role resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,46 @@
+package edgedelta
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceRoleImport(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/orgs/" + testOrgID + "/roles":
+			_, _ = w.Write([]byte(`[
+				{"role_id": "` + testRoleID + `", "name": "pipeline-editor", "permissions": ["pipelines:read", "pipelines:write"]},
+				{"role_id": "` + testOtherRoleID + `", "name": "admin", "permissions": ["*"], "built_in": true}
+			]`))
+		case "/v1/orgs/" + testOrgID + "/roles/" + testOtherRoleID:
+			_, _ = w.Write([]byte(`{"role_id": "` + testOtherRoleID + `", "name": "admin", "permissions": ["*"], "built_in": true}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := resourceRole().Data(nil)
+	d.SetId("*")
+	results, err := resourceRoleImport(context.Background(), d, newTestProviderMetadata(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Id() != testRoleID {
+		t.Fatalf("expected only the custom role to be imported, got %d roles", len(results))
+	}
+	if got := results[0].Get("permissions.#").(int); got != 2 {
+		t.Errorf("expected 2 permissions, got %d", got)
+	}
+
+	d = resourceRole().Data(nil)
+	d.SetId(testOtherRoleID)
+	_, err = resourceRoleImport(context.Background(), d, newTestProviderMetadata(server.URL))
+	if err == nil || !strings.Contains(err.Error(), "built-in role") {
+		t.Errorf("expected a built-in role error, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceTeam() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTeamCreate,
+		ReadContext:   resourceTeamRead,
+		UpdateContext: resourceTeamUpdate,
+		DeleteContext: resourceTeamDelete,
+		Description:   "Manages an EdgeDelta team and its members.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the team.",
+			},
+
+			// Optional
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the team.",
+			},
+			"members": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the users in the team.",
+			},
+
+			// Computed
+			"team_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Unique identifier for the team.",
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of team creation.",
+			},
+			"updated": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of last update.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTeamImport,
+		},
+	}
+}
+
+// resourceTeamImport imports a single team ID, a comma-separated list of IDs or every team ("*")
+func resourceTeamImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	meta := m.(*ProviderMetadata)
+	teamID := strings.TrimSpace(d.Id())
+	if teamID == "" {
+		return nil, fmt.Errorf("could not determine the resource ID - possibly the ID was not set")
+	}
+
+	var teams []*Team
+	if teamID == "*" {
+		var err error
+		teams, err = meta.client.GetAllTeams(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get teams from API: %s", err)
+		}
+	} else {
+		for _, id := range strings.Split(teamID, ",") {
+			if id = strings.TrimSpace(id); id == "" {
+				continue
+			}
+			resp, err := meta.client.GetTeam(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("could not get team from API: %s (team ID was: '%s')", err, id)
+			}
+			teams = append(teams, (*Team)(resp))
+		}
+	}
+
+	results := make([]*schema.ResourceData, 0, len(teams))
+	for _, t := range teams {
+		dd := resourceTeam().Data(nil)
+		dd.SetId(t.TeamID)
+		diags := setTeamState(dd, t, nil)
+		if len(diags) > 0 {
+			return nil, fmt.Errorf("failed to set team state: %s", diags[0].Detail)
+		}
+		results = append(results, dd)
+	}
+	return results, nil
+}
+
+func buildTeam(d *schema.ResourceData) *Team {
+	return &Team{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Members:     interfaceSliceToStringSlice(d.Get("members").(*schema.Set).List()),
+	}
+}
+
+func setTeamState(d *schema.ResourceData, t *Team, diags diag.Diagnostics) diag.Diagnostics {
+	diags = setWithError(d, "team_id", t.TeamID, diags)
+	diags = setWithError(d, "name", t.Name, diags)
+	diags = setWithError(d, "description", t.Description, diags)
+	diags = setWithError(d, "members", stringSliceToInterface(t.Members), diags)
+	diags = setWithError(d, "created", t.Created, diags)
+	diags = setWithError(d, "updated", t.Updated, diags)
+	return diags
+}
+
+func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.CreateTeam(ctx, buildTeam(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not create the team resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId(resp.TeamID)
+	return setTeamState(d, (*Team)(resp), diags)
+}
+
+func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.GetTeam(ctx, d.Id())
+	if err != nil {
+		// Check if resource was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not read the team resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	return setTeamState(d, (*Team)(resp), diags)
+}
+
+func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	if !d.HasChanges("name", "description", "members") {
+		return diags
+	}
+
+	resp, err := meta.client.UpdateTeam(ctx, d.Id(), buildTeam(d))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not update the team resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	return setTeamState(d, (*Team)(resp), diags)
+}
+
+func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+	err := meta.client.DeleteTeam(ctx, d.Id())
+	if err != nil {
+		// If already deleted, just remove from state
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not delete the team resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,60 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceTeamCreate(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var team Team
+		if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if len(team.Members) != 2 {
+			t.Errorf("expected 2 members in the request, got %v", team.Members)
+		}
+		team.TeamID = testTeamID
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(team)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceTeam().Schema, map[string]interface{}{
+		"name":    "sre",
+		"members": []interface{}{testUserID, testOtherUserID},
+	})
+
+	diags := resourceTeamCreate(context.Background(), d, newTestProviderMetadata(server.URL))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if d.Id() != testTeamID {
+		t.Errorf("expected ID %s, got %q", testTeamID, d.Id())
+	}
+	if got := d.Get("members").(*schema.Set).Len(); got != 2 {
+		t.Errorf("expected 2 members, got %d", got)
+	}
+}
+
+func TestResourceTeamRead_NotFound(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceTeam().Schema, map[string]interface{}{"name": "sre"})
+	d.SetId(testTeamID)
+
+	diags := resourceTeamRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the team to be removed from state, got ID %q", d.Id())
+	}
+}
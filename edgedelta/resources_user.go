@@ -0,0 +1,269 @@
+package edgedelta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// What happens to a user when the resource is destroyed
+const (
+	revokeUserOnDestroy = "revoke"
+	deleteUserOnDestroy = "delete"
+)
+
+func resourceUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Description:   "Manages the membership of a user in the EdgeDelta organization.",
+		Schema: map[string]*schema.Schema{
+			// Required
+			"email": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringMatch(emailAddressRegexp, "must be an email address"),
+				DiffSuppressFunc: suppressCaseInsensitive,
+				Description:      "Email address the invitation is sent to, case-insensitive. Changing it replaces the user.",
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Name of a built-in role or ID of an edgedelta_role.",
+			},
+
+			// Optional
+			"on_destroy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      revokeUserOnDestroy,
+				ValidateFunc: validation.StringInSlice([]string{revokeUserOnDestroy, deleteUserOnDestroy}, false),
+				Description:  "What to do with the user when the resource is destroyed: revoke its access and keep its record (revoke), or delete it (delete). Defaults to revoke.",
+			},
+
+			// Computed
+			"user_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Unique identifier for the user.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name the user set when accepting the invitation.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Membership status of the user (invited, active).",
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of the invitation.",
+			},
+			"updated": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UTC timestamp of last update.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUserImport,
+		},
+	}
+}
+
+// resourceUserImport imports users by ID or email, as a single value, a comma-separated list or every user ("*")
+func resourceUserImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	meta := m.(*ProviderMetadata)
+	importID := strings.TrimSpace(d.Id())
+	if importID == "" {
+		return nil, fmt.Errorf("could not determine the resource ID - possibly the ID was not set")
+	}
+
+	var users []*User
+	if importID == "*" || strings.Contains(importID, "@") {
+		all, err := meta.client.GetAllUsers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get users from API: %s", err)
+		}
+		if importID == "*" {
+			for _, u := range all {
+				if u != nil && u.Status != RevokedUserStatus {
+					users = append(users, u)
+				}
+			}
+		} else {
+			for _, id := range strings.Split(importID, ",") {
+				if id = strings.TrimSpace(id); id == "" {
+					continue
+				}
+				u := findUser(all, id)
+				if u == nil {
+					return nil, fmt.Errorf("could not find a user with ID or email '%s'", id)
+				}
+				users = append(users, u)
+			}
+		}
+	} else {
+		for _, id := range strings.Split(importID, ",") {
+			if id = strings.TrimSpace(id); id == "" {
+				continue
+			}
+			resp, err := meta.client.GetUser(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("could not get user from API: %s (user ID was: '%s')", err, id)
+			}
+			users = append(users, (*User)(resp))
+		}
+	}
+
+	results := make([]*schema.ResourceData, 0, len(users))
+	for _, u := range users {
+		dd := resourceUser().Data(nil)
+		dd.SetId(u.UserID)
+		// Imported resources have no configuration to take the defaults from
+		diags := setWithError(dd, "on_destroy", revokeUserOnDestroy, nil)
+		diags = setUserState(dd, u, diags)
+		if len(diags) > 0 {
+			return nil, fmt.Errorf("failed to set user state: %s", diags[0].Detail)
+		}
+		results = append(results, dd)
+	}
+	return results, nil
+}
+
+// findUser returns the user whose ID or email (case-insensitive) is idOrEmail, or nil
+func findUser(users []*User, idOrEmail string) *User {
+	for _, u := range users {
+		if u != nil && (u.UserID == idOrEmail || strings.EqualFold(u.Email, idOrEmail)) {
+			return u
+		}
+	}
+	return nil
+}
+
+func setUserState(d *schema.ResourceData, u *User, diags diag.Diagnostics) diag.Diagnostics {
+	diags = setWithError(d, "user_id", u.UserID, diags)
+	// Emails are case-insensitive, keep the configured case when the API returns another one
+	email := u.Email
+	if configured := d.Get("email").(string); strings.EqualFold(configured, email) {
+		email = configured
+	}
+	diags = setWithError(d, "email", email, diags)
+	diags = setWithError(d, "role", u.Role, diags)
+	diags = setWithError(d, "name", u.Name, diags)
+	diags = setWithError(d, "status", string(u.Status), diags)
+	diags = setWithError(d, "created", u.Created, diags)
+	diags = setWithError(d, "updated", u.Updated, diags)
+	return diags
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.InviteUser(ctx, &User{
+		Email: d.Get("email").(string),
+		Role:  d.Get("role").(string),
+	})
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not invite the user",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId(resp.UserID)
+	return setUserState(d, (*User)(resp), diags)
+}
+
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	resp, err := meta.client.GetUser(ctx, d.Id())
+	if err != nil {
+		// Check if resource was deleted outside Terraform
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not read the user resource",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+	// A revoked user is no longer a member, so it is invited again on the next apply
+	if resp.Status == RevokedUserStatus {
+		d.SetId("")
+		return diags
+	}
+
+	return setUserState(d, (*User)(resp), diags)
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+
+	// on_destroy only lives in the Terraform state
+	if !d.HasChange("role") {
+		return diags
+	}
+
+	resp, err := meta.client.UpdateUser(ctx, d.Id(), &User{
+		Email: d.Get("email").(string),
+		Role:  d.Get("role").(string),
+	})
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Could not update the role of the user",
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	return setUserState(d, (*User)(resp), diags)
+}
+
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := m.(*ProviderMetadata)
+	var diags diag.Diagnostics
+	var err error
+	if d.Get("on_destroy").(string) == deleteUserOnDestroy {
+		err = meta.client.DeleteUser(ctx, d.Id())
+	} else {
+		err = meta.client.RevokeUser(ctx, d.Id())
+	}
+	if err != nil {
+		// If already deleted, just remove from state
+		if IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Could not %s the user", d.Get("on_destroy").(string)),
+			Detail:   apiErrorDetail(err),
+		})
+		return diags
+	}
+
+	d.SetId("")
+	return diags
+}
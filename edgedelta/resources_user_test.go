@@ -0,0 +1,155 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceUserImport_ByEmail(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/orgs/"+testOrgID+"/users" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*User{
+			{UserID: testUserID, Email: "Jane.Doe@example.com", Role: "admin", Status: ActiveUserStatus},
+			{UserID: testOtherUserID, Email: "ops@example.com", Role: "viewer", Status: RevokedUserStatus},
+		})
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		id      string
+		wantIDs []string
+		wantErr bool
+	}{
+		{name: "email is case-insensitive", id: "jane.doe@example.com", wantIDs: []string{testUserID}},
+		{name: "emails and IDs", id: "jane.doe@example.com, " + testOtherUserID, wantIDs: []string{testUserID, testOtherUserID}},
+		{name: "all skips revoked users", id: "*", wantIDs: []string{testUserID}},
+		{name: "unknown email", id: "nobody@example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := resourceUser().Data(nil)
+			d.SetId(tt.id)
+			results, err := resourceUserImport(context.Background(), d, newTestProviderMetadata(server.URL))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != len(tt.wantIDs) {
+				t.Fatalf("expected %d users, got %d", len(tt.wantIDs), len(results))
+			}
+			for i, want := range tt.wantIDs {
+				if results[i].Id() != want {
+					t.Errorf("expected user %d to be %s, got %s", i, want, results[i].Id())
+				}
+				if got := results[i].Get("on_destroy").(string); got != revokeUserOnDestroy {
+					t.Errorf("expected on_destroy to default to revoke, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceUserDelete_OnDestroy(t *testing.T) {
+	tests := []struct {
+		onDestroy  string
+		wantMethod string
+		wantPath   string
+	}{
+		{onDestroy: "revoke", wantMethod: http.MethodPost, wantPath: "/v1/orgs/" + testOrgID + "/users/" + testUserID + "/revoke"},
+		{onDestroy: "delete", wantMethod: http.MethodDelete, wantPath: "/v1/orgs/" + testOrgID + "/users/" + testUserID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.onDestroy, func(t *testing.T) {
+			requests := 0
+			server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.Method != tt.wantMethod || r.URL.Path != tt.wantPath {
+					t.Errorf("expected %s %s, got %s %s", tt.wantMethod, tt.wantPath, r.Method, r.URL.Path)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			})
+			defer server.Close()
+
+			d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+				"email":      "jane.doe@example.com",
+				"role":       "admin",
+				"on_destroy": tt.onDestroy,
+			})
+			d.SetId(testUserID)
+
+			diags := resourceUserDelete(context.Background(), d, newTestProviderMetadata(server.URL))
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if requests != 1 {
+				t.Errorf("expected 1 request, got %d", requests)
+			}
+			if d.Id() != "" {
+				t.Errorf("expected the user to be removed from state, got ID %q", d.Id())
+			}
+		})
+	}
+}
+
+func TestResourceUserRead_Revoked(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user_id": "` + testUserID + `", "email": "jane.doe@example.com", "role": "admin", "status": "revoked"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+		"email": "jane.doe@example.com",
+		"role":  "admin",
+	})
+	d.SetId(testUserID)
+
+	diags := resourceUserRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the revoked user to be removed from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceUserRead_EmailCase(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user_id": "` + testUserID + `", "email": "Jane.Doe@Example.com", "role": "admin", "status": "active"}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+		"email": "jane.doe@example.com",
+		"role":  "admin",
+	})
+	d.SetId(testUserID)
+
+	diags := resourceUserRead(context.Background(), d, newTestProviderMetadata(server.URL))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got := d.Get("email").(string); got != "jane.doe@example.com" {
+		t.Errorf("expected the configured email to be kept, got %q", got)
+	}
+	if !suppressCaseInsensitive("email", "Jane.Doe@Example.com", "jane.doe@example.com", d) {
+		t.Error("expected a change of case only to be suppressed")
+	}
+	if suppressCaseInsensitive("email", "jane.doe@example.com", "john.doe@example.com", d) {
+		t.Error("expected a different email not to be suppressed")
+	}
+}
@@ -0,0 +1,101 @@
+package edgedelta
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// State upgraders migrate the raw state of older schema versions to the current one.
+// When a resource schema changes in a way that existing state can't be read with, bump its
+// SchemaVersion, freeze the previous schema as resource<Name>V<N>() below and append an
+// upgrader for version N. Upgraders run in order, each one only has to handle a single step.
+
+// resourceConfigV1 is the edgedelta_config schema of version 1
+func resourceConfigV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"config_content": {Type: schema.TypeString, Required: true},
+			"environment":    {Type: schema.TypeString, Required: true},
+			"fleet_type":     {Type: schema.TypeString, Optional: true},
+			"conf_id":        {Type: schema.TypeString, Optional: true},
+			"fleet_subtype":  {Type: schema.TypeString, Optional: true},
+			"cluster_name":   {Type: schema.TypeString, Optional: true},
+			"description":    {Type: schema.TypeString, Optional: true},
+			"auto_deploy":    {Type: schema.TypeBool, Optional: true},
+			"tag":            {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// resourceConfigStateUpgradeV1 adds the attributes introduced in version 2. Configs that were
+// managed via conf_id were never created by Terraform, so they become adopted rather than owned.
+func resourceConfigStateUpgradeV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+	if _, ok := rawState["lifecycle_mode"]; !ok {
+		if confID, _ := rawState["conf_id"].(string); confID != "" {
+			rawState["lifecycle_mode"] = string(AdoptLifecycleMode)
+		} else {
+			rawState["lifecycle_mode"] = string(OwnLifecycleMode)
+		}
+	}
+	if _, ok := rawState["auto_deploy"]; !ok {
+		rawState["auto_deploy"] = true
+	}
+	if _, ok := rawState["deletion_protection"]; !ok {
+		rawState["deletion_protection"] = false
+	}
+	if _, ok := rawState["restore_on_destroy"]; !ok {
+		rawState["restore_on_destroy"] = false
+	}
+	return rawState, nil
+}
+
+// resourceDashboardV1 is the edgedelta_dashboard schema of version 1
+func resourceDashboardV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"dashboard_name": {Type: schema.TypeString, Required: true},
+			"description":    {Type: schema.TypeString, Optional: true},
+			"tags":           {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"definition":     {Type: schema.TypeString, Optional: true},
+			"dashboard_id":   {Type: schema.TypeString, Computed: true},
+			"creator":        {Type: schema.TypeString, Computed: true},
+			"updater":        {Type: schema.TypeString, Computed: true},
+			"created":        {Type: schema.TypeString, Computed: true},
+			"updated":        {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// resourceDashboardStateUpgradeV1 adds the attributes introduced in version 2
+func resourceDashboardStateUpgradeV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+	if _, ok := rawState["deletion_protection"]; !ok {
+		rawState["deletion_protection"] = false
+	}
+	return rawState, nil
+}
+
+// resourceDashboardV2 is the edgedelta_dashboard schema of version 2
+func resourceDashboardV2() *schema.Resource {
+	r := resourceDashboardV1()
+	r.Schema["deletion_protection"] = &schema.Schema{Type: schema.TypeBool, Optional: true}
+	return r
+}
+
+// resourceDashboardStateUpgradeV2 adds the attributes introduced in version 3
+func resourceDashboardStateUpgradeV2(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+	// The accesses in the definition were managed by the dashboard before
+	if _, ok := rawState["manage_resource_accesses"]; !ok {
+		rawState["manage_resource_accesses"] = true
+	}
+	return rawState, nil
+}
@@ -0,0 +1,156 @@
+package edgedelta
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestStateUpgraders(t *testing.T) {
+	tests := []struct {
+		name     string
+		upgrade  schema.StateUpgradeFunc
+		oldState string
+		want     string
+	}{
+		{
+			name:    "config created by terraform",
+			upgrade: resourceConfigStateUpgradeV1,
+			oldState: `{
+				"id": "` + testConfigID + `",
+				"conf_id": "",
+				"config_content": "a: b",
+				"environment": "Linux",
+				"fleet_type": "Edge",
+				"auto_deploy": false,
+				"tag": "prod"
+			}`,
+			want: `{
+				"id": "` + testConfigID + `",
+				"conf_id": "",
+				"config_content": "a: b",
+				"environment": "Linux",
+				"fleet_type": "Edge",
+				"auto_deploy": false,
+				"tag": "prod",
+				"lifecycle_mode": "own",
+				"deletion_protection": false,
+				"restore_on_destroy": false
+			}`,
+		},
+		{
+			name:    "config managed via conf_id",
+			upgrade: resourceConfigStateUpgradeV1,
+			oldState: `{
+				"id": "` + testConfigID + `",
+				"conf_id": "` + testConfigID + `",
+				"config_content": "a: b",
+				"environment": "Kubernetes",
+				"fleet_subtype": "Edge"
+			}`,
+			want: `{
+				"id": "` + testConfigID + `",
+				"conf_id": "` + testConfigID + `",
+				"config_content": "a: b",
+				"environment": "Kubernetes",
+				"fleet_subtype": "Edge",
+				"auto_deploy": true,
+				"lifecycle_mode": "adopt",
+				"deletion_protection": false,
+				"restore_on_destroy": false
+			}`,
+		},
+		{
+			name:    "config already upgraded",
+			upgrade: resourceConfigStateUpgradeV1,
+			oldState: `{
+				"conf_id": "` + testConfigID + `",
+				"auto_deploy": false,
+				"lifecycle_mode": "own",
+				"deletion_protection": true,
+				"restore_on_destroy": true
+			}`,
+			want: `{
+				"conf_id": "` + testConfigID + `",
+				"auto_deploy": false,
+				"lifecycle_mode": "own",
+				"deletion_protection": true,
+				"restore_on_destroy": true
+			}`,
+		},
+		{
+			name:    "dashboard",
+			upgrade: resourceDashboardStateUpgradeV1,
+			oldState: `{
+				"id": "` + testDashboardID + `",
+				"dashboard_name": "Test Dashboard",
+				"tags": ["a", "b"],
+				"definition": "{}"
+			}`,
+			want: `{
+				"id": "` + testDashboardID + `",
+				"dashboard_name": "Test Dashboard",
+				"tags": ["a", "b"],
+				"definition": "{}",
+				"deletion_protection": false
+			}`,
+		},
+		{
+			name:    "dashboard v2",
+			upgrade: resourceDashboardStateUpgradeV2,
+			oldState: `{
+				"id": "` + testDashboardID + `",
+				"dashboard_name": "Test Dashboard",
+				"definition": "{}",
+				"deletion_protection": true
+			}`,
+			want: `{
+				"id": "` + testDashboardID + `",
+				"dashboard_name": "Test Dashboard",
+				"definition": "{}",
+				"deletion_protection": true,
+				"manage_resource_accesses": true
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldState, want map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.oldState), &oldState); err != nil {
+				t.Fatalf("invalid old state: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("invalid expected state: %v", err)
+			}
+			got, err := tt.upgrade(context.Background(), oldState, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected upgraded state:\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestStateUpgraders_CoverEveryVersion(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"edgedelta_config":    resourceConfig(),
+		"edgedelta_dashboard": resourceDashboard(),
+	}
+	for name, r := range resources {
+		t.Run(name, func(t *testing.T) {
+			if len(r.StateUpgraders) != r.SchemaVersion-1 {
+				t.Fatalf("expected %d state upgraders for schema version %d, got %d", r.SchemaVersion-1, r.SchemaVersion, len(r.StateUpgraders))
+			}
+			for i, u := range r.StateUpgraders {
+				if u.Version != i+1 {
+					t.Errorf("state upgrader %d: expected version %d, got %d", i, i+1, u.Version)
+				}
+			}
+		})
+	}
+}
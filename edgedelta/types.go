@@ -26,6 +26,16 @@ const (
 	GatewayFleetSubtype     FleetSubtype = "Gateway"
 )
 
+// LifecycleMode controls what happens to an edgedelta_config on destroy
+type LifecycleMode string
+
+const (
+	// OwnLifecycleMode is a config created (or explicitly taken over) by Terraform, it is deleted on destroy
+	OwnLifecycleMode LifecycleMode = "own"
+	// AdoptLifecycleMode is a pre-existing config managed via conf_id, it is left in place (or restored) on destroy
+	AdoptLifecycleMode LifecycleMode = "adopt"
+)
+
 type Config struct {
 	Content      string          `json:"content"`
 	Description  string          `json:"description"`
@@ -59,6 +69,9 @@ type DeployConfigResponse struct {
 	LastUpdated string `json:"lastUpdated,omitempty"`
 }
 
+// DeployedConfigHistoryStatus is the ConfigHistory.Status of a version that has been deployed
+const DeployedConfigHistoryStatus = "deployed"
+
 type ConfigHistory struct {
 	ConfigID  string `json:"config_id"`
 	Timestamp int64  `json:"timestamp"`
@@ -66,6 +79,24 @@ type ConfigHistory struct {
 	Status    string `json:"status"`
 }
 
+// AgentStatus is the state of a single agent running a pipeline
+type AgentStatus struct {
+	AgentID  string `json:"agent_id"`
+	Hostname string `json:"hostname,omitempty"`
+	// ConfigVersion is the ConfigHistory.Timestamp of the config the agent is running
+	ConfigVersion int64  `json:"config_version"`
+	Healthy       bool   `json:"healthy"`
+	LastSeen      string `json:"last_seen,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// PipelineStatus is the deployment state of a config across its fleet
+type PipelineStatus struct {
+	ConfigID        string        `json:"config_id"`
+	DeployedVersion int64         `json:"deployed_version"`
+	Agents          []AgentStatus `json:"agents"`
+}
+
 // Dashboard represents an EdgeDelta dashboard
 type Dashboard struct {
 	OrgID                   string                   `json:"org_id,omitempty"`
@@ -86,3 +117,182 @@ type Dashboard struct {
 type GetDashboardResponse Dashboard
 type CreateDashboardResponse Dashboard
 type UpdateDashboardResponse Dashboard
+
+// MonitorType is the kind of condition a monitor evaluates
+type MonitorType string
+
+const (
+	ThresholdMonitorType MonitorType = "threshold"
+	AnomalyMonitorType   MonitorType = "anomaly"
+)
+
+// MonitorThreshold triggers when the query result compared with Operator to Value is true
+type MonitorThreshold struct {
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value"`
+}
+
+// MonitorAnomaly triggers when the query result deviates from its baseline
+type MonitorAnomaly struct {
+	Sensitivity string `json:"sensitivity"`
+	Direction   string `json:"direction"`
+}
+
+type Monitor struct {
+	OrgID               string            `json:"org_id,omitempty"`
+	MonitorID           string            `json:"monitor_id,omitempty"`
+	Name                string            `json:"name"`
+	Description         string            `json:"description,omitempty"`
+	Type                MonitorType       `json:"type"`
+	Query               string            `json:"query"`
+	Threshold           *MonitorThreshold `json:"threshold,omitempty"`
+	Anomaly             *MonitorAnomaly   `json:"anomaly,omitempty"`
+	EvaluationWindow    string            `json:"evaluation_window"`
+	Severity            string            `json:"severity"`
+	NotificationTargets []string          `json:"notification_targets,omitempty"`
+	Muted               bool              `json:"muted"`
+	Tags                []string          `json:"tags,omitempty"`
+	Creator             string            `json:"creator,omitempty"`
+	Updater             string            `json:"updater,omitempty"`
+	Created             string            `json:"created,omitempty"`
+	Updated             string            `json:"updated,omitempty"`
+}
+
+// Monitor API response types
+type GetMonitorResponse Monitor
+type CreateMonitorResponse Monitor
+type UpdateMonitorResponse Monitor
+
+// NotificationChannelType is the kind of destination a notification channel sends alerts to
+type NotificationChannelType string
+
+const (
+	SlackNotificationChannelType     NotificationChannelType = "slack"
+	PagerDutyNotificationChannelType NotificationChannelType = "pagerduty"
+	TeamsNotificationChannelType     NotificationChannelType = "teams"
+	WebhookNotificationChannelType   NotificationChannelType = "webhook"
+	EmailNotificationChannelType     NotificationChannelType = "email"
+)
+
+// Secret settings of the notification channels are write-only, the API returns them empty or masked
+type SlackChannelSettings struct {
+	WebhookURL string `json:"webhook_url,omitempty"`
+	Channel    string `json:"channel,omitempty"`
+}
+
+type PagerDutyChannelSettings struct {
+	RoutingKey string `json:"routing_key,omitempty"`
+}
+
+type TeamsChannelSettings struct {
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+type WebhookChannelSettings struct {
+	URL     string            `json:"url,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type EmailChannelSettings struct {
+	Addresses []string `json:"addresses"`
+}
+
+type NotificationChannel struct {
+	OrgID       string                    `json:"org_id,omitempty"`
+	ChannelID   string                    `json:"channel_id,omitempty"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description,omitempty"`
+	Type        NotificationChannelType   `json:"type"`
+	Slack       *SlackChannelSettings     `json:"slack,omitempty"`
+	PagerDuty   *PagerDutyChannelSettings `json:"pagerduty,omitempty"`
+	Teams       *TeamsChannelSettings     `json:"teams,omitempty"`
+	Webhook     *WebhookChannelSettings   `json:"webhook,omitempty"`
+	Email       *EmailChannelSettings     `json:"email,omitempty"`
+	Creator     string                    `json:"creator,omitempty"`
+	Updater     string                    `json:"updater,omitempty"`
+	Created     string                    `json:"created,omitempty"`
+	Updated     string                    `json:"updated,omitempty"`
+}
+
+// Notification channel API response types
+type GetNotificationChannelResponse NotificationChannel
+type CreateNotificationChannelResponse NotificationChannel
+type UpdateNotificationChannelResponse NotificationChannel
+
+// APIToken is an organization API token. Token holds the secret and is only returned on creation.
+type APIToken struct {
+	OrgID       string   `json:"org_id,omitempty"`
+	TokenID     string   `json:"token_id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Scopes      []string `json:"scopes"`
+	ExpiresAt   string   `json:"expires_at,omitempty"`
+	Token       string   `json:"token,omitempty"`
+	Creator     string   `json:"creator,omitempty"`
+	Created     string   `json:"created,omitempty"`
+	LastUsed    string   `json:"last_used,omitempty"`
+}
+
+// API token API response types
+type GetAPITokenResponse APIToken
+type CreateAPITokenResponse APIToken
+
+// UserStatus is the membership status of a user in the organization
+type UserStatus string
+
+const (
+	InvitedUserStatus UserStatus = "invited"
+	ActiveUserStatus  UserStatus = "active"
+	RevokedUserStatus UserStatus = "revoked"
+)
+
+// User is a member of the organization. Role is the name of a built-in role or the ID of a custom role.
+type User struct {
+	OrgID   string     `json:"org_id,omitempty"`
+	UserID  string     `json:"user_id,omitempty"`
+	Email   string     `json:"email"`
+	Name    string     `json:"name,omitempty"`
+	Role    string     `json:"role"`
+	Status  UserStatus `json:"status,omitempty"`
+	Created string     `json:"created,omitempty"`
+	Updated string     `json:"updated,omitempty"`
+}
+
+// User API response types
+type GetUserResponse User
+type InviteUserResponse User
+type UpdateUserResponse User
+
+// Team groups users of the organization, Members are user IDs
+type Team struct {
+	OrgID       string   `json:"org_id,omitempty"`
+	TeamID      string   `json:"team_id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Members     []string `json:"members"`
+	Created     string   `json:"created,omitempty"`
+	Updated     string   `json:"updated,omitempty"`
+}
+
+// Team API response types
+type GetTeamResponse Team
+type CreateTeamResponse Team
+type UpdateTeamResponse Team
+
+// Role is a named set of permissions that can be assigned to users
+type Role struct {
+	OrgID       string   `json:"org_id,omitempty"`
+	RoleID      string   `json:"role_id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"built_in,omitempty"`
+	Created     string   `json:"created,omitempty"`
+	Updated     string   `json:"updated,omitempty"`
+}
+
+// Role API response types
+type GetRoleResponse Role
+type CreateRoleResponse Role
+type UpdateRoleResponse Role
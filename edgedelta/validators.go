@@ -3,10 +3,15 @@ package edgedelta
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"path"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
 )
 
 // validateJSON validates that a string is valid JSON
@@ -22,6 +27,61 @@ func validateJSON(val interface{}, key string) (warns []string, errs []error) {
 	return warns, errs
 }
 
+// validatePipelineYAML validates that a string is valid YAML and, for v3 pipelines,
+// that it matches the embedded pipeline schema. It doesn't need network access.
+// Link-graph warnings are reported here as well, since a CustomizeDiff can only return errors
+// (the graph errors themselves are enforced by customizeDiffPipelineGraph).
+func validatePipelineYAML(val interface{}, key string) (warns []string, errs []error) {
+	v := val.(string)
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	pipelines, pipelineWarns, pipelineErrs := parsePipeline(v)
+	for _, p := range pipelines {
+		graphWarns, _ := validatePipelineGraph(p)
+		for _, w := range graphWarns {
+			pipelineWarns = append(pipelineWarns, documentPrefix(p.Document)+w)
+		}
+	}
+	for _, w := range pipelineWarns {
+		warns = append(warns, fmt.Sprintf("%q: %s", key, w))
+	}
+	for _, err := range pipelineErrs {
+		errs = append(errs, fmt.Errorf("%q: %v", key, err))
+	}
+	return warns, errs
+}
+
+// validateDuration validates that a string is a valid, positive Go duration (e.g. "30s", "10m")
+func validateDuration(val interface{}, key string) (warns []string, errs []error) {
+	v := val.(string)
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid duration (e.g. 30s, 10m), got: %s", key, v))
+		return warns, errs
+	}
+	if d <= 0 {
+		errs = append(errs, fmt.Errorf("%q must be a positive duration, got: %s", key, v))
+	}
+	return warns, errs
+}
+
+// validateConfigVersion returns a validator for config history versions. Versions are millisecond
+// timestamps, which overflow schema.TypeInt on 32-bit platforms, so they are declared as
+// schema.TypeFloat and must be whole numbers of at least min.
+func validateConfigVersion(min float64) schema.SchemaValidateFunc {
+	return func(val interface{}, key string) (warns []string, errs []error) {
+		v := val.(float64)
+		if v < min || v != math.Trunc(v) || v > maxExactFloatInt {
+			errs = append(errs, fmt.Errorf("%q must be a whole number of at least %.0f, got: %v", key, min, v))
+		}
+		return warns, errs
+	}
+}
+
+// maxExactFloatInt is the largest integer that float64 represents exactly (2^53)
+const maxExactFloatInt = 1 << 53
+
 // suppressEquivalentJSON is a DiffSuppressFunc that suppresses diffs for equivalent JSON
 func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
 	if old == "" && new == "" {
@@ -42,6 +102,149 @@ func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
 	return reflect.DeepEqual(oldJSON, newJSON)
 }
 
+// suppressCaseInsensitive is a DiffSuppressFunc that suppresses diffs that only change the case
+func suppressCaseInsensitive(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// suppressEquivalentYAML is a DiffSuppressFunc that suppresses diffs for semantically equivalent YAML.
+// Whitespace, key order, quoting style and comments are ignored, aliases are resolved
+// and every document of a multi-document file is compared.
+func suppressEquivalentYAML(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	if old == "" || new == "" {
+		return false
+	}
+
+	oldYAML, err := normalizeYAML(old)
+	if err != nil {
+		return false
+	}
+	newYAML, err := normalizeYAML(new)
+	if err != nil {
+		return false
+	}
+
+	return yamlValuesEqual(oldYAML, newYAML)
+}
+
+// normalizeYAML decodes all documents in s into comparable trees.
+// Empty documents (e.g. a trailing "---") are dropped.
+func normalizeYAML(s string) ([]interface{}, error) {
+	dec := yaml.NewDecoder(strings.NewReader(s))
+	docs := []interface{}{}
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal YAML: %v", err)
+		}
+		if doc == nil {
+			continue
+		}
+		docs = append(docs, normalizeYAMLValue(doc))
+	}
+	return docs, nil
+}
+
+// yamlMapKey is a map key normalized by normalizeYAMLValue. The key keeps its type, so that
+// e.g. 1 and "1" or true and "true" stay different keys.
+type yamlMapKey struct {
+	typ   string
+	value string
+}
+
+// normalizeYAMLValue converts a decoded YAML value into a canonical form:
+// map keys become yamlMapKey and signed integers become int64. Integers are not converted to
+// float64, which would lose precision above 2^53, see yamlValuesEqual for how numbers compare.
+func normalizeYAMLValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[yamlMapKey]interface{}, len(t))
+		for k, val := range t {
+			out[normalizeYAMLMapKey(k)] = normalizeYAMLValue(val)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[yamlMapKey]interface{}, len(t))
+		for k, val := range t {
+			out[normalizeYAMLMapKey(k)] = normalizeYAMLValue(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = normalizeYAMLValue(val)
+		}
+		return out
+	case int:
+		return int64(t)
+	default:
+		return v
+	}
+}
+
+// normalizeYAMLMapKey converts a decoded YAML map key into a yamlMapKey
+func normalizeYAMLMapKey(k interface{}) yamlMapKey {
+	k = normalizeYAMLValue(k)
+	return yamlMapKey{typ: fmt.Sprintf("%T", k), value: fmt.Sprint(k)}
+}
+
+// yamlValuesEqual compares values normalized by normalizeYAMLValue. Integers only equal
+// integers and compare exactly, and floats only equal floats, so that e.g. 1 and 1.0 differ.
+func yamlValuesEqual(a, b interface{}) bool {
+	switch at := a.(type) {
+	case map[yamlMapKey]interface{}:
+		bt, ok := b.(map[yamlMapKey]interface{})
+		if !ok || len(at) != len(bt) {
+			return false
+		}
+		for k, av := range at {
+			bv, ok := bt[k]
+			if !ok || !yamlValuesEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bt, ok := b.([]interface{})
+		if !ok || len(at) != len(bt) {
+			return false
+		}
+		for i := range at {
+			if !yamlValuesEqual(at[i], bt[i]) {
+				return false
+			}
+		}
+		return true
+	case float64:
+		bf, ok := b.(float64)
+		return ok && (at == bf || (math.IsNaN(at) && math.IsNaN(bf)))
+	case int64:
+		switch bt := b.(type) {
+		case int64:
+			return at == bt
+		case uint64:
+			return at >= 0 && uint64(at) == bt
+		}
+		return false
+	case uint64:
+		switch bt := b.(type) {
+		case int64:
+			return bt >= 0 && uint64(bt) == at
+		case uint64:
+			return at == bt
+		}
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
 // stringSliceToInterface converts a []string to []interface{} for Terraform state
 func stringSliceToInterface(s []string) []interface{} {
 	result := make([]interface{}, len(s))
@@ -89,3 +292,11 @@ func stringToJSONMap(s string) (map[string]interface{}, error) {
 	}
 	return result, nil
 }
+
+// validateGlob checks that the value is a valid path.Match pattern
+func validateGlob(val interface{}, key string) (warns []string, errs []error) {
+	if _, err := path.Match(val.(string), ""); err != nil {
+		errs = append(errs, fmt.Errorf("%q is not a valid glob pattern: %v", key, err))
+	}
+	return
+}
@@ -0,0 +1,177 @@
+package edgedelta
+
+import "testing"
+
+func TestSuppressEquivalentYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{
+			name: "identical",
+			old:  "version: v3\n",
+			new:  "version: v3\n",
+			want: true,
+		},
+		{
+			name: "whitespace and indentation",
+			old:  "settings:\n  tag: prod\n",
+			new:  "settings:\n    tag:   prod\n\n",
+			want: true,
+		},
+		{
+			name: "key order",
+			old:  "a: 1\nb: 2\n",
+			new:  "b: 2\na: 1\n",
+			want: true,
+		},
+		{
+			name: "quoting style",
+			old:  "tag: prod\n",
+			new:  "tag: \"prod\"\n",
+			want: true,
+		},
+		{
+			name: "comments",
+			old:  "tag: prod\n",
+			new:  "# the tag\ntag: prod # inline\n",
+			want: true,
+		},
+		{
+			name: "flow vs block style",
+			old:  "include:\n  - a\n  - b\n",
+			new:  "include: [a, b]\n",
+			want: true,
+		},
+		{
+			name: "integer vs float",
+			old:  "ratio: 1\n",
+			new:  "ratio: 1.0\n",
+			want: false,
+		},
+		{
+			name: "integers above 2^53 compare exactly",
+			old:  "id: 9007199254740993\n",
+			new:  "id: 9007199254740992\n",
+			want: false,
+		},
+		{
+			name: "floats compare as floats",
+			old:  "ratio: 1.0\n",
+			new:  "ratio: 1.00\n",
+			want: true,
+		},
+		{
+			name: "integer above 2^53 vs equal float",
+			old:  "id: 9007199254740992\n",
+			new:  "id: 9007199254740992.0\n",
+			want: false,
+		},
+		{
+			name: "unsigned integers compare exactly",
+			old:  "id: 18446744073709551615\n",
+			new:  "id: 18446744073709551614\n",
+			want: false,
+		},
+		{
+			name: "integer vs fractional float",
+			old:  "ratio: 1\n",
+			new:  "ratio: 1.5\n",
+			want: false,
+		},
+		{
+			name: "integer vs string map key",
+			old:  "1: a\n",
+			new:  "\"1\": a\n",
+			want: false,
+		},
+		{
+			name: "bool vs string map key",
+			old:  "true: a\n",
+			new:  "\"true\": a\n",
+			want: false,
+		},
+		{
+			name: "integer map keys",
+			old:  "1: a\n2: b\n",
+			new:  "2: b\n1: a\n",
+			want: true,
+		},
+		{
+			name: "not a number",
+			old:  "ratio: .nan\n",
+			new:  "ratio: .NaN\n",
+			want: true,
+		},
+		{
+			name: "quoted number is a string",
+			old:  "port: 80\n",
+			new:  "port: \"80\"\n",
+			want: false,
+		},
+		{
+			name: "anchors and aliases",
+			old:  "base: &b\n  level: info\nlog: *b\n",
+			new:  "base:\n  level: info\nlog:\n  level: info\n",
+			want: true,
+		},
+		{
+			name: "merge keys",
+			old:  "base: &b\n  level: info\nlog:\n  <<: *b\n  format: json\n",
+			new:  "base:\n  level: info\nlog:\n  level: info\n  format: json\n",
+			want: true,
+		},
+		{
+			name: "multi-document equal",
+			old:  "a: 1\n---\nb: 2\n",
+			new:  "---\na: 1\n---\nb: 2\n---\n",
+			want: true,
+		},
+		{
+			name: "multi-document differs in second document",
+			old:  "a: 1\n---\nb: 2\n",
+			new:  "a: 1\n---\nb: 3\n",
+			want: false,
+		},
+		{
+			name: "multi-document order matters",
+			old:  "a: 1\n---\nb: 2\n",
+			new:  "b: 2\n---\na: 1\n",
+			want: false,
+		},
+		{
+			name: "value change",
+			old:  "tag: prod\n",
+			new:  "tag: staging\n",
+			want: false,
+		},
+		{
+			name: "list order matters",
+			old:  "include: [a, b]\n",
+			new:  "include: [b, a]\n",
+			want: false,
+		},
+		{
+			name: "invalid YAML",
+			old:  "tag: prod\n",
+			new:  "tag: [prod\n",
+			want: false,
+		},
+		{
+			name: "empty old value",
+			old:  "",
+			new:  "tag: prod\n",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressEquivalentYAML("config_content", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressEquivalentYAML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}